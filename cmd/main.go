@@ -1,41 +1,278 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	httpadapter "github.com/JCzapla/dep-dashboard/internal/adapter/inbound/http"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/inbound/schedule"
 	depsdev "github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/email"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	postgresadapter "github.com/JCzapla/dep-dashboard/internal/adapter/outbound/postgres"
 	sqliteadapter "github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlite"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlstore"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/webhook"
 	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
 	"github.com/JCzapla/dep-dashboard/internal/service"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+type repository interface {
+	outbound.Repository
+	outbound.AlertRepository
+	outbound.DigestRepository
+	outbound.WebhookRepository
+}
+
 func main() {
-	db, err := sql.Open("sqlite3", "./deps.db?busy_timeout=5000")
-	if err != nil {
-		log.Fatalf("Open db error: %v", err)
+	storage := flag.String("storage", os.Getenv("STORAGE"), "storage backend: sqlite, postgres or memory")
+	flag.Parse()
+	command := flag.Arg(0)
+	var args []string
+	if flag.NArg() > 1 {
+		args = flag.Args()[1:]
 	}
-	defer db.Close()
 
-	repo, err := sqliteadapter.NewRepository(db)
-	if err != nil {
-		log.Fatalf("Repository init error: %v", err)
+	var repo repository
+	if *storage == "memory" {
+		if command == "migrate" || command == "check" {
+			log.Fatalf("Command %s is not available for memory storage", command)
+		}
+		repo = memory.NewRepository()
+	} else {
+		db, err := openDatabase(*storage)
+		if err != nil {
+			log.Fatalf("Open db error: %v", err)
+		}
+		defer db.Close()
+
+		if command == "migrate" {
+			newMigrator := sqliteadapter.NewMigrator
+			if *storage == "postgres" {
+				newMigrator = postgresadapter.NewMigrator
+			}
+			migrator, err := newMigrator(db)
+			if err != nil {
+				log.Fatalf("Migrate error: %v", err)
+			}
+			if err := runMigrate(context.Background(), migrator, args); err != nil {
+				log.Fatalf("Migrate error: %v", err)
+			}
+			return
+		}
+
+		if *storage == "postgres" {
+			repo, err = postgresadapter.NewRepository(db)
+		} else {
+			repo, err = sqliteadapter.NewRepository(db)
+		}
+		if err != nil {
+			log.Fatalf("Repository init error: %v", err)
+		}
+	}
+
+	if command == "check" {
+		sqliteRepo, ok := repo.(*sqliteadapter.Repository)
+		if !ok {
+			log.Fatalf("Integrity check is only available for SQLite storage")
+		}
+		if err := runCheck(context.Background(), sqliteRepo, args); err != nil {
+			log.Fatalf("Integrity check error: %v", err)
+		}
+		return
 	}
 
 	client := depsdev.NewClient(&http.Client{Timeout: 10 * time.Second})
-	service := service.NewDependencyService(repo,client)
+	dispatcher := webhook.NewDispatcher(&http.Client{Timeout: 10 * time.Second}, repo, webhook.Config{
+		MaxAttempts: 5,
+		InitialBackoff: 2 * time.Second,
+	})
+
+	digestPeriod, err := parseDigestFrequency(os.Getenv("DIGEST_FREQUENCY"))
+	if err != nil {
+		log.Fatalf("Digest config error: %v", err)
+	}
+	digests := service.NewDigestService(repo, repo, repo, email.NewSender(email.Config{
+		Addr: os.Getenv("SMTP_ADDR"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From: os.Getenv("SMTP_FROM"),
+		To: splitList(os.Getenv("DIGEST_RECIPIENTS")),
+		Timeout: 30 * time.Second,
+	}), service.DigestConfig{Period: digestPeriod})
+
+	if command == "digest" {
+		if err := digests.SendDigest(context.Background()); err != nil {
+			log.Fatalf("Digest error: %v", err)
+		}
+		return
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if os.Getenv("DIGEST_FREQUENCY") != "" {
+		go schedule.NewDigest(digests).Run(ctx)
+	}
+
+	health := service.DefaultHealthWeights()
+	if weights := os.Getenv("HEALTH_WEIGHTS"); weights != "" {
+		if err := json.Unmarshal([]byte(weights), &health); err != nil {
+			log.Fatalf("Health weights config error: %v", err)
+		}
+	}
+	projectMaxAge := 24 * time.Hour
+	if maxAge := os.Getenv("PROJECT_MAX_AGE"); maxAge != "" {
+		if projectMaxAge, err = time.ParseDuration(maxAge); err != nil {
+			log.Fatalf("Project max age config error: %v", err)
+		}
+	}
+	snapshotRetention := 100
+	if retention := os.Getenv("SNAPSHOT_RETENTION"); retention != "" {
+		if snapshotRetention, err = strconv.Atoi(retention); err != nil || snapshotRetention < 0 {
+			log.Fatalf("Snapshot retention config error: invalid count %q", retention)
+		}
+	}
+	service := service.NewDependencyService(repo, repo, repo, client, dispatcher, service.Config{
+		AlertScoreThreshold: 4.0,
+		Health: health,
+		ProjectMaxAge: projectMaxAge,
+		SnapshotRetention: snapshotRetention,
+	})
+
 	config := httpadapter.Config{
 		DefaultPackage: domain.PackageRef{
 			Name: "express",
 			Version: "5.2.1",
 		},
 	}
-	router := httpadapter.NewRouter(service, config)
-	if err := http.ListenAndServe(":8080", router); err != nil {
+	server := &http.Server{Addr: ":8080", Handler: httpadapter.NewRouter(service, config)}
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-failed:
 		log.Fatalf("Server Failed: %v", err)
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		log.Printf("Server shutdown error: %v", err)
+	}
+	if err := dispatcher.Shutdown(shutdown); err != nil {
+		log.Printf("%v", err)
+	}
+}
+
+func openDatabase(storage string) (*sql.DB, error) {
+	switch storage {
+	case "", "sqlite":
+		return sql.Open("sqlite3", sqliteadapter.DSN("./deps.db"))
+	case "postgres":
+		return sql.Open("pgx", os.Getenv("DATABASE_URL"))
+	default:
+		return nil, fmt.Errorf("Unknown storage: %q", storage)
+	}
+}
+
+func runMigrate(ctx context.Context, migrator *sqlstore.Migrator, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("Invalid step count: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migration(s)\n", reverted)
+	case "status":
+		migrations, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			state := "pending"
+			if migration.AppliedAt != nil {
+				state = "applied " + migration.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", migration.Version, migration.Name, state)
+		}
+	default:
+		return fmt.Errorf("Unknown migrate command: %q", command)
+	}
+	return nil
+}
+
+func runCheck(ctx context.Context, repo *sqliteadapter.Repository, args []string) error {
+	repair := len(args) > 0 && args[0] == "--repair"
+	if len(args) > 0 && !repair {
+		return fmt.Errorf("Unknown check argument: %q", args[0])
+	}
+	issues, err := repo.CheckIntegrity(ctx, repair)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+	for _, issue := range issues {
+		state := "found"
+		if issue.Repaired {
+			state = "repaired"
+		}
+		fmt.Printf("%s: %d %s\n", issue.Check, issue.Count, state)
+	}
+	return nil
+}
+
+func parseDigestFrequency(frequency string) (time.Duration, error) {
+	switch frequency {
+	case "", "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("Unknown digest frequency: %q", frequency)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-}
\ No newline at end of file
+	return items
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+const badgeLabel = "dependencies"
+
+var badgeColors = map[domain.ScoreBand]string{
+	domain.ScoreBandGreen:  "#4c1",
+	domain.ScoreBandYellow: "#fe7d37",
+	domain.ScoreBandRed:    "#e05d44",
+	domain.ScoreBandNil:    "#9f9f9f",
+}
+
+func (h *Handler) Badge(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+	if !ok || name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	status := http.StatusOK
+	var modified time.Time
+	var svg []byte
+	summary, err := h.service.SummarizePackage(r.Context(), name)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+		svg = renderBadge(badgeLabel, "not tracked", badgeColors[domain.ScoreBandNil])
+	case err != nil:
+		status = http.StatusInternalServerError
+		svg = renderBadge(badgeLabel, "error", badgeColors[domain.ScoreBandNil])
+	default:
+		modified = summary.LastUpdatedAt
+		svg = renderBadge(badgeLabel, badgeValue(summary), badgeColors[domain.ScoreBandOf(summary.Health)])
+	}
+
+	sum := sha256.Sum256(svg)
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	if status != http.StatusOK {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(status)
+		w.Write(svg)
+		return
+	}
+	http.ServeContent(w, r, "", modified, bytes.NewReader(svg))
+}
+
+func badgeValue(summary *domain.PackageSummary) string {
+	value := "no health"
+	if summary.Health != nil {
+		value = fmt.Sprintf("health %.1f", *summary.Health)
+	}
+	switch summary.Advisories {
+	case 0:
+		return value
+	case 1:
+		return value + " | 1 advisory"
+	default:
+		return fmt.Sprintf("%s | %d advisories", value, summary.Advisories)
+	}
+}
+
+func renderBadge(label, value, color string) []byte {
+	labelWidth := textWidth(label)
+	valueWidth := textWidth(value)
+	width := labelWidth + valueWidth
+	label = html.EscapeString(label)
+	value = html.EscapeString(value)
+
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, value)
+	fmt.Fprintf(&svg, `<title>%s: %s</title>`, label, value)
+	svg.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&svg, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	svg.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&svg, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&svg, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, valueWidth, color)
+	fmt.Fprintf(&svg, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	svg.WriteString(`</g>`)
+	svg.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&svg, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, labelWidth/2, label, labelWidth/2, label)
+	fmt.Fprintf(&svg, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, labelWidth+valueWidth/2, value, labelWidth+valueWidth/2, value)
+	svg.WriteString(`</g></svg>`)
+	return svg.Bytes()
+}
+
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)*7 + 10
+}
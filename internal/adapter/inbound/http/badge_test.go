@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+)
+
+func TestBadge(t *testing.T) {
+	router, repo := newTestRouter(t)
+	saveHealth(t, repo, "green", 7.5, 2.0)
+	saveHealth(t, repo, "yellow", 4.0, 9.1)
+	saveHealth(t, repo, "red", 3.9, 9.1)
+	savePackage(t, repo, "unscored")
+	saveHealth(t, repo, "@scope/pkg", 8.0, 8.0)
+
+	tests := []struct {
+		name string
+		target string
+		status int
+		color string
+		value string
+	}{
+		{name: "green at 7.5", target: "/badge/green.svg", status: http.StatusOK, color: "#4c1", value: "health 7.5"},
+		{name: "yellow at 4.0", target: "/badge/yellow.svg", status: http.StatusOK, color: "#fe7d37", value: "health 4.0"},
+		{name: "red below 4.0", target: "/badge/red.svg", status: http.StatusOK, color: "#e05d44", value: "health 3.9"},
+		{name: "no health", target: "/badge/unscored.svg", status: http.StatusOK, color: "#9f9f9f", value: "no health"},
+		{name: "scoped name", target: "/badge/@scope/pkg.svg", status: http.StatusOK, color: "#4c1", value: "health 8.0"},
+		{name: "escaped scoped name", target: "/badge/%40scope%2Fpkg.svg", status: http.StatusOK, color: "#4c1", value: "health 8.0"},
+		{name: "untracked", target: "/badge/unknown.svg", status: http.StatusNotFound, color: "#9f9f9f", value: "not tracked"},
+		{name: "untracked scoped", target: "/badge/@scope/unknown.svg", status: http.StatusNotFound, color: "#9f9f9f", value: "not tracked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, tt.target)
+			if w.Code != tt.status {
+				t.Fatalf("Got status %d, expected %d", w.Code, tt.status)
+			}
+			svg := w.Body.String()
+			if !strings.Contains(svg, `fill="`+tt.color+`"`) || !strings.Contains(svg, tt.value) {
+				t.Errorf("Got %s, expected color %s and %q", svg, tt.color, tt.value)
+			}
+		})
+	}
+}
+
+func TestBadgeRequiresSVG(t *testing.T) {
+	router, repo := newTestRouter(t)
+	savePackage(t, repo, "green", 9.1)
+
+	if w := serve(router, http.MethodGet, "/badge/green.png"); w.Code != http.StatusNotFound {
+		t.Errorf("Got status %d, expected 404", w.Code)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		expected int
+	}{
+		{name: "ascii", text: "deps", expected: 38},
+		{name: "multibyte", text: "zależności", expected: 80},
+		{name: "empty", text: "", expected: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textWidth(tt.text); got != tt.expected {
+				t.Errorf("Got width %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func saveHealth(t *testing.T, repo *memory.Repository, name string, health float64, scores ...float64) {
+	pkg := savePackage(t, repo, name, scores...)
+	pkg.Health = &health
+	if err := repo.Save(context.Background(), pkg, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+}
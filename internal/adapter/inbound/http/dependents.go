@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type dependentsData struct {
+	Dependency string
+	Version string
+	Dependents []domain.Dependent
+	Error string
+}
+
+func (h *Handler) FindDependents(w http.ResponseWriter, r *http.Request) {
+	data := dependentsData{
+		Dependency: r.PathValue("dep"),
+		Version: r.URL.Query().Get("version"),
+	}
+	if data.Dependency == "" {
+		data.Dependency = strings.TrimSpace(r.URL.Query().Get("dep"))
+	}
+
+	var failure error
+	if data.Dependency == "" {
+		failure = domain.Errorf(domain.KindInvalidInput, "Dependency name is required")
+	} else {
+		data.Dependents, failure = h.service.FindDependents(r.Context(), data.Dependency, data.Version)
+	}
+	status := http.StatusOK
+	if failure != nil {
+		status = errorStatus(failure)
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		if failure != nil {
+			data.Error = errorDetail(r, failure)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		h.tmpl.ExecuteTemplate(w, "dependents.html", data)
+		return
+	}
+
+	if failure != nil {
+		writeError(w, r, failure)
+		return
+	}
+	resp := DependentsResponse{
+		Dependency: data.Dependency,
+		Version: data.Version,
+		Dependents: make([]DependentResponse, len(data.Dependents)),
+	}
+	for i, dependent := range data.Dependents {
+		resp.Dependents[i] = toDependentResponse(dependent)
+	}
+	writeJSON(w, status, resp)
+}
+
+func toDependentResponse(dependent domain.Dependent) DependentResponse {
+	return DependentResponse{
+		Package: dependent.Package.Name,
+		PackageVersion: dependent.Package.Version,
+		Version: dependent.Version,
+		Relation: dependent.Relation,
+		Depth: dependent.Depth,
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestFindDependents(t *testing.T) {
+	router, repo := newTestRouter(t)
+	savePackage(t, repo, "app", 7)
+	savePackage(t, repo, "api", 5)
+
+	tests := []struct {
+		name string
+		target string
+		status int
+		expected []string
+	}{
+		{name: "path", target: "/dependents/app-dep-0", status: http.StatusOK, expected: []string{"app"}},
+		{name: "query", target: "/dependents?dep=api-dep-0", status: http.StatusOK, expected: []string{"api"}},
+		{name: "version", target: "/dependents/app-dep-0?version=1.0.0", status: http.StatusOK, expected: []string{"app"}},
+		{name: "other version", target: "/dependents/app-dep-0?version=2.0.0", status: http.StatusOK},
+		{name: "missing dependency", target: "/dependents/lodash", status: http.StatusOK},
+		{name: "no dependency", target: "/dependents", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, tt.target)
+			if w.Code != tt.status {
+				t.Fatalf("Got status %d, expected %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var response DependentsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if response.Dependents == nil {
+				t.Errorf("Got null dependents, expected a list")
+			}
+			var packages []string
+			for _, dependent := range response.Dependents {
+				packages = append(packages, dependent.Package)
+			}
+			if len(packages) != len(tt.expected) || (len(packages) > 0 && packages[0] != tt.expected[0]) {
+				t.Errorf("Got packages %v, expected %v", packages, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (h *Handler) GetDependencyDetail(w http.ResponseWriter, r *http.Request) {
+	detail, err := h.service.GetDependencyDetail(r.Context(), r.PathValue("name"), r.PathValue("dep"))
+	status := http.StatusOK
+	if err != nil {
+		status = errorStatus(err)
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		data := struct {
+			Detail *domain.DependencyDetail
+			Error string
+		}{Detail: detail}
+		if err != nil {
+			data.Error = errorDetail(r, err)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		h.tmpl.ExecuteTemplate(w, "detail.html", data)
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, status, toDetailResponse(detail))
+}
+
+func toDetailResponse(detail *domain.DependencyDetail) DependencyDetailResponse {
+	resp := DependencyDetailResponse{
+		Package: detail.Package.Name,
+		Name: detail.Name,
+		Versions: make([]DependencyNode, len(detail.Versions)),
+		Paths: make([][]string, len(detail.Paths)),
+		ScoreHistory: make([]ScorePointResponse, len(detail.ScoreHistory)),
+		Advisories: make([]AdvisoryResponse, len(detail.Advisories)),
+		SourceURL: detail.SourceURL,
+		UsedBy: make([]DependentResponse, len(detail.UsedBy)),
+	}
+	for i, node := range detail.Versions {
+		resp.Versions[i] = toNodeResponse(node)
+	}
+	for i, path := range detail.Paths {
+		resp.Paths[i] = make([]string, len(path))
+		for j, ref := range path {
+			resp.Paths[i][j] = ref.Name + "@" + ref.Version
+		}
+	}
+	for i, point := range detail.ScoreHistory {
+		resp.ScoreHistory[i] = ScorePointResponse{TakenAt: point.TakenAt, Version: point.Version, Score: point.Score}
+	}
+	for i, advisory := range detail.Advisories {
+		resp.Advisories[i] = AdvisoryResponse{ID: advisory.ID, Title: advisory.Title, CVSS3Score: advisory.CVSS3Score}
+	}
+	for i, dependent := range detail.UsedBy {
+		resp.UsedBy[i] = toDependentResponse(dependent)
+	}
+	if detail.Scorecard != nil {
+		resp.Scorecard = &ScorecardResponse{
+			Score: detail.Scorecard.Score,
+			Date: detail.Scorecard.Date,
+			Checks: make([]ScorecardCheckResponse, len(detail.Scorecard.Checks)),
+		}
+		for i, check := range detail.Scorecard.Checks {
+			resp.Scorecard.Checks[i] = ScorecardCheckResponse{
+				Name: check.Name,
+				Score: check.Score,
+				Reason: check.Reason,
+				Documentation: check.Documentation,
+			}
+		}
+	}
+	return resp
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	internalErrorDetail = "The server failed to process the request, details are in the server log"
+)
+
+type problemKind struct {
+	status int
+	title string
+}
+
+var problemKinds = map[domain.ErrorKind]problemKind{
+	domain.KindNotFound: {status: http.StatusNotFound, title: "Resource not found"},
+	domain.KindUpstreamNotFound: {status: http.StatusNotFound, title: "Package not found on deps.dev"},
+	domain.KindUpstreamUnavailable: {status: http.StatusBadGateway, title: "deps.dev is unavailable"},
+	domain.KindInvalidInput: {status: http.StatusBadRequest, title: "Invalid input"},
+	domain.KindConflict: {status: http.StatusConflict, title: "Conflict with the current state"},
+}
+
+func errorStatus(err error) int {
+	if kind, ok := problemKinds[domain.KindOf(err)]; ok {
+		return kind.status
+	}
+	return http.StatusInternalServerError
+}
+
+func errorDetail(r *http.Request, err error) string {
+	if _, ok := problemKinds[domain.KindOf(err)]; ok {
+		return err.Error()
+	}
+	log.Printf("%s %s error: %v", r.Method, r.URL.Path, err)
+	return internalErrorDetail
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := ProblemResponse{
+		Type: "about:blank",
+		Title: http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: errorDetail(r, err),
+		Instance: r.URL.Path,
+	}
+	kind := domain.KindOf(err)
+	if known, ok := problemKinds[kind]; ok {
+		problem.Type = "urn:dep-dashboard:problem:" + string(kind)
+		problem.Title = known.title
+		problem.Status = known.status
+	}
+	writeProblem(w, problem)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, ProblemResponse{
+		Type: "about:blank",
+		Title: http.StatusText(http.StatusMethodNotAllowed),
+		Status: http.StatusMethodNotAllowed,
+		Detail: r.Method + " is not supported by " + r.URL.Path,
+		Instance: r.URL.Path,
+	})
+}
+
+func writeProblem(w http.ResponseWriter, problem ProblemResponse) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name string
+		err error
+		status int
+		title string
+		problemType string
+		detail string
+	}{
+		{name: "not found", err: domain.ErrNotFound, status: http.StatusNotFound, title: "Resource not found", problemType: "urn:dep-dashboard:problem:not_found"},
+		{name: "wrapped", err: fmt.Errorf("Lookup error: %w", domain.ErrWebhookNotFound), status: http.StatusNotFound, title: "Resource not found", problemType: "urn:dep-dashboard:problem:not_found"},
+		{name: "upstream not found", err: domain.Errorf(domain.KindUpstreamNotFound, "Error from deps.dev: 404"), status: http.StatusNotFound, title: "Package not found on deps.dev", problemType: "urn:dep-dashboard:problem:upstream_not_found"},
+		{name: "upstream unavailable", err: domain.Errorf(domain.KindUpstreamUnavailable, "Error from deps.dev: 503"), status: http.StatusBadGateway, title: "deps.dev is unavailable", problemType: "urn:dep-dashboard:problem:upstream_unavailable"},
+		{name: "invalid input", err: fmt.Errorf("%w: unknown format %q", domain.ErrInvalidWebhook, "xml"), status: http.StatusBadRequest, title: "Invalid input", problemType: "urn:dep-dashboard:problem:invalid_input"},
+		{name: "conflict", err: domain.Errorf(domain.KindConflict, "Refresh in progress"), status: http.StatusConflict, title: "Conflict with the current state", problemType: "urn:dep-dashboard:problem:conflict"},
+		{name: "untyped", err: errors.New("Saving package error: disk full"), status: http.StatusInternalServerError, title: "Internal Server Error", problemType: "about:blank", detail: internalErrorDetail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeError(w, httptest.NewRequest(http.MethodDelete, "/deps/express", nil), tt.err)
+
+			if w.Code != tt.status || w.Header().Get("Content-Type") != problemContentType {
+				t.Fatalf("Got %d %s, expected %d %s", w.Code, w.Header().Get("Content-Type"), tt.status, problemContentType)
+			}
+			var problem ProblemResponse
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			expected := ProblemResponse{Type: tt.problemType, Title: tt.title, Status: tt.status, Detail: cmp.Or(tt.detail, tt.err.Error()), Instance: "/deps/express"}
+			if problem != expected {
+				t.Errorf("Got %+v, expected %+v", problem, expected)
+			}
+		})
+	}
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) ProblemResponse {
+	t.Helper()
+	if w.Header().Get("Content-Type") != problemContentType {
+		t.Fatalf("Got content type %s, expected %s", w.Header().Get("Content-Type"), problemContentType)
+	}
+	var problem ProblemResponse
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	return problem
+}
+
+func TestErrorResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		method string
+		target string
+		expected ProblemResponse
+	}{
+		{name: "delete missing", method: http.MethodDelete, target: "/deps/missing", expected: ProblemResponse{Type: "urn:dep-dashboard:problem:not_found", Title: "Resource not found", Status: http.StatusNotFound, Detail: "Package not found", Instance: "/deps/missing"}},
+		{name: "get missing", method: http.MethodGet, target: "/deps/missing", expected: ProblemResponse{Type: "urn:dep-dashboard:problem:not_found", Title: "Resource not found", Status: http.StatusNotFound, Detail: "Package not found", Instance: "/deps/missing"}},
+		{name: "invalid limit", method: http.MethodGet, target: "/deps/app?limit=abc", expected: ProblemResponse{Type: "urn:dep-dashboard:problem:invalid_input", Title: "Invalid input", Status: http.StatusBadRequest, Detail: "Limit must be a number between 1 and 1000", Instance: "/deps/app"}},
+		{name: "limit out of range", method: http.MethodGet, target: "/deps/app?limit=0", expected: ProblemResponse{Type: "urn:dep-dashboard:problem:invalid_input", Title: "Invalid input", Status: http.StatusBadRequest, Detail: "Limit must be a number between 1 and 1000", Instance: "/deps/app"}},
+		{name: "invalid query", method: http.MethodGet, target: "/deps/app?q=score%3E%3E", expected: ProblemResponse{Type: "urn:dep-dashboard:problem:invalid_input", Title: "Invalid input", Status: http.StatusBadRequest, Detail: "Unexpected end of query", Instance: "/deps/app"}},
+		{name: "method not allowed", method: http.MethodPatch, target: "/deps/app", expected: ProblemResponse{Type: "about:blank", Title: "Method Not Allowed", Status: http.StatusMethodNotAllowed, Detail: "PATCH is not supported by /deps/app", Instance: "/deps/app"}},
+		{name: "collection method not allowed", method: http.MethodDelete, target: "/deps", expected: ProblemResponse{Type: "about:blank", Title: "Method Not Allowed", Status: http.StatusMethodNotAllowed, Detail: "DELETE is not supported by /deps", Instance: "/deps"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, repo := newTestRouter(t)
+			savePackage(t, repo, "app", 5)
+
+			w := serve(router, tt.method, tt.target)
+			if w.Code != tt.expected.Status {
+				t.Fatalf("Got %d, expected %d: %s", w.Code, tt.expected.Status, w.Body.String())
+			}
+			if problem := decodeProblem(t, w); problem != tt.expected {
+				t.Errorf("Got %+v, expected %+v", problem, tt.expected)
+			}
+		})
+	}
+}
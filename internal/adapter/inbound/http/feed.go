@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+const feedLimit = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated time.Time   `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated time.Time   `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	snapshots, err := h.service.ListHistory(r.Context(), name, feedLimit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	base := baseURL(r)
+	feed := atomFeed{
+		Title: "Dependency Dashboard changes",
+		ID: base + r.URL.Path,
+		Author: atomAuthor{Name: "Dependency Dashboard"},
+		Link: atomLink{Href: base + r.URL.Path, Rel: "self"},
+	}
+	if name != "" {
+		feed.Title = fmt.Sprintf("%s dependency changes", name)
+	}
+	feed.Updated = time.Now().UTC()
+	if len(snapshots) > 0 {
+		feed.Updated = snapshots[0].TakenAt
+	}
+	for _, snapshot := range snapshots {
+		feed.Entries = append(feed.Entries, toAtomEntry(base, snapshot))
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(feed)
+}
+
+func toAtomEntry(base string, snapshot domain.Snapshot) atomEntry {
+	title := fmt.Sprintf("%s %s refreshed", snapshot.PackageRef.Name, snapshot.PackageRef.Version)
+	lines := make([]string, len(snapshot.Changes))
+	for i, change := range snapshot.Changes {
+		lines[i] = change.String()
+	}
+	body := "No changes since the previous refresh"
+	if len(lines) > 0 {
+		title = fmt.Sprintf("%s, %d change(s)", title, len(lines))
+		body = strings.Join(lines, "\n")
+	}
+
+	return atomEntry{
+		Title: title,
+		ID: fmt.Sprintf("%s/snapshots/%d", base, snapshot.ID),
+		Updated: snapshot.TakenAt,
+		Link: atomLink{Href: base + "/"},
+		Content: atomContent{Type: "text", Body: body},
+	}
+}
+
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestFeed(t *testing.T) {
+	router, repo := newTestRouter(t)
+	takenAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "express", Version: "5.2.1"},
+		Dependencies: []domain.DependencyNode{{Name: "qs", Version: "6.14.0", Relation: "DIRECT"}},
+		LastUpdatedAt: takenAt,
+	}
+	changes := []domain.Change{{Kind: domain.ChangeAdded, Dependency: "qs", To: "6.14.0"}}
+	if err := repo.Save(context.Background(), pkg, changes); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := repo.Save(context.Background(), &domain.Package{PackageRef: domain.PackageRef{Name: "empty", Version: "1.0.0"}, LastUpdatedAt: takenAt}, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		target string
+		status int
+		title string
+		entries int
+	}{
+		{name: "all packages", target: "/feed.atom", status: http.StatusOK, title: "Dependency Dashboard changes", entries: 2},
+		{name: "tracked package", target: "/deps/express/feed.atom", status: http.StatusOK, title: "express dependency changes", entries: 1},
+		{name: "untracked package", target: "/deps/unknown/feed.atom", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, tt.target)
+			if w.Code != tt.status {
+				t.Fatalf("Got status %d, expected %d", w.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				if w.Header().Get("Content-Type") != problemContentType {
+					t.Errorf("Got content type %q, expected problem", w.Header().Get("Content-Type"))
+				}
+				return
+			}
+
+			var feed atomFeed
+			if err := xml.NewDecoder(w.Body).Decode(&feed); err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if feed.Title != tt.title || len(feed.Entries) != tt.entries || feed.Updated.IsZero() {
+				t.Errorf("Got feed %q with %d entries updated %v, expected %q with %d", feed.Title, len(feed.Entries), feed.Updated, tt.title, tt.entries)
+			}
+			for _, entry := range feed.Entries {
+				if entry.ID == "" || entry.Updated.IsZero() || entry.Content.Body == "" {
+					t.Errorf("Got incomplete entry %+v", entry)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedWithoutSnapshots(t *testing.T) {
+	router, _ := newTestRouter(t)
+	before := time.Now().UTC().Add(-time.Second)
+
+	w := serve(router, http.MethodGet, "/feed.atom")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Got status %d, expected 200", w.Code)
+	}
+	var feed atomFeed
+	if err := xml.NewDecoder(w.Body).Decode(&feed); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if feed.Updated.Before(before) || len(feed.Entries) != 0 {
+		t.Errorf("Got updated %v with %d entries, expected current time and no entries", feed.Updated, len(feed.Entries))
+	}
+}
@@ -2,21 +2,90 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/JCzapla/dep-dashboard/internal/domain"
 	"github.com/JCzapla/dep-dashboard/internal/port/inbound"
 )
 
 
+const (
+	defaultPageSize = 100
+	maxPageSize = 1000
+)
+
 type indexData struct {
 	Package *domain.Package
+	Path string
 	Filter string
 	MinScore string
+	Query string
+	Sort string
+	Limit string
+	Cursor string
+	Outdated string
+	Deprecated string
 	Error string
+	Failed int
+	Alerts []domain.Alert
+	Packages []domain.PackageSummary
+}
+
+func (d indexData) SortURL(column string) string {
+	sort := column
+	if d.Sort == column {
+		sort = "-" + column
+	}
+	return d.pageURL(sort, "")
+}
+
+func (d indexData) SortIndicator(column string) string {
+	switch d.Sort {
+	case column:
+		return " ▲"
+	case "-" + column:
+		return " ▼"
+	default:
+		return ""
+	}
+}
+
+func (d indexData) NextURL() string {
+	if d.Package == nil || d.Package.Page == nil || d.Package.Page.NextCursor == "" {
+		return ""
+	}
+	return d.pageURL(d.Sort, d.Package.Page.NextCursor)
+}
+
+func (d indexData) FirstURL() string {
+	if d.Cursor == "" {
+		return ""
+	}
+	return d.pageURL(d.Sort, "")
+}
+
+func (d indexData) pageURL(sort, cursor string) string {
+	params := url.Values{}
+	for key, value := range map[string]string{
+		"name": d.Filter,
+		"minScore": d.MinScore,
+		"q": d.Query,
+		"limit": d.Limit,
+		"outdated": d.Outdated,
+		"deprecated": d.Deprecated,
+		"sort": sort,
+		"cursor": cursor,
+	} {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+	return d.Path + "?" + params.Encode()
 }
 
 type Handler struct {
@@ -37,12 +106,12 @@ func (h *Handler) PutDeps(w http.ResponseWriter, r *http.Request) {
 	hasBody := r.ContentLength != 0
 	hasPath := name != ""
 	if hasBody && hasPath {
-		writeJSON(w, http.StatusBadRequest, "Used both: URL Path Param and Body. Use one")
+		writeError(w, r, domain.Errorf(domain.KindInvalidInput, "Used both: URL Path Param and Body. Use one"))
 		return
 	}
 	if hasBody {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeJSON(w, http.StatusBadRequest, "Invalid request body")
+			writeError(w, r, domain.Errorf(domain.KindInvalidInput, "Invalid request body"))
 			return
 		}
 		if req.Name == "" {
@@ -55,7 +124,7 @@ func (h *Handler) PutDeps(w http.ResponseWriter, r *http.Request) {
 
 	pkg, err := h.service.StoreDependencies(r.Context(), req.Name)
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, fmt.Errorf("%w", err))
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, toResponse(pkg))
@@ -65,6 +134,7 @@ func (h *Handler) PutDeps(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) GetDeps(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
+	isHTML := strings.Contains(r.Header.Get("Accept"), "text/html")
 	var filters []domain.Filter
 	if param := q.Get("name"); param != "" {
 		filters = append(filters, domain.Filter{Column: "name", Operator: domain.FilterEq, Value: param})
@@ -73,22 +143,135 @@ func (h *Handler) GetDeps(w http.ResponseWriter, r *http.Request) {
 		filters = append(filters, domain.Filter{Column: "score", Operator: domain.FilterGte, Value: param})
 	}
 
-	data := indexData{Filter: q.Get("name"), MinScore: q.Get("minScore")}
-	pkg, err := h.service.GetDependencies(r.Context(), filters)
-	if err != nil {
-		data.Error = err.Error()
-	} else {
+	name := r.PathValue("name")
+	var failure error
+	data := indexData{
+		Path: "/deps",
+		Filter: q.Get("name"),
+		MinScore: q.Get("minScore"),
+		Query: q.Get("q"),
+		Sort: q.Get("sort"),
+		Limit: q.Get("limit"),
+		Cursor: q.Get("cursor"),
+		Outdated: q.Get("outdated"),
+		Deprecated: q.Get("deprecated"),
+	}
+	switch data.Outdated {
+	case "":
+	case "true":
+		filters = append(filters, domain.Filter{Logic: domain.LogicOr, Filters: []domain.Filter{
+			{Column: "major_behind", Operator: domain.FilterGt, Value: "0"},
+			{Column: "minor_behind", Operator: domain.FilterGt, Value: "0"},
+			{Column: "patch_behind", Operator: domain.FilterGt, Value: "0"},
+		}})
+	case "false":
+		filters = append(filters,
+			domain.Filter{Column: "major_behind", Operator: domain.FilterEq, Value: "0"},
+			domain.Filter{Column: "minor_behind", Operator: domain.FilterEq, Value: "0"},
+			domain.Filter{Column: "patch_behind", Operator: domain.FilterEq, Value: "0"},
+		)
+	default:
+		failure = domain.Errorf(domain.KindInvalidInput, "Outdated must be true or false")
+	}
+	if data.Deprecated != "" {
+		filters = append(filters, domain.Filter{Column: "deprecated", Operator: domain.FilterEq, Value: data.Deprecated})
+	}
+	if name != "" {
+		data.Path = "/deps/" + url.PathEscape(name)
+	}
+	if param := q.Get("q"); param != "" {
+		filter, err := parseQuery(param)
+		if err != nil {
+			failure = domain.NewError(domain.KindInvalidInput, err)
+		}
+		filters = append(filters, filter)
+	}
+
+	query := domain.NodeQuery{Filters: filters, Cursor: data.Cursor, Limit: defaultPageSize}
+	if data.Sort != "" {
+		query.Sort = domain.Sort{
+			Column: strings.TrimPrefix(data.Sort, "-"),
+			Desc: strings.HasPrefix(data.Sort, "-"),
+		}
+	}
+	if data.Limit != "" {
+		limit, err := strconv.Atoi(data.Limit)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			failure = domain.Errorf(domain.KindInvalidInput, "Limit must be a number between 1 and %d", maxPageSize)
+		}
+		query.Limit = limit
+	}
+
+	var pkg *domain.Package
+	if failure == nil {
+		pkg, failure = h.service.GetDependencies(r.Context(), name, query)
+	}
+	if failure == nil {
 		data.Package = pkg
 	}
 
-	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+	if isHTML {
+		if failure != nil {
+			data.Error = errorDetail(r, failure)
+		}
+		var err error
+		if data.Alerts, err = h.service.ListAlerts(r.Context(), false); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if data.Packages, err = h.service.ListPackages(r.Context()); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		for _, summary := range data.Packages {
+			if pkg != nil && summary.PackageRef.Name == pkg.PackageRef.Name {
+				data.Failed = len(summary.Failed)
+			}
+		}
 		w.Header().Set("Content-Type", "text/html")
 		h.tmpl.ExecuteTemplate(w, "index.html", data)
 		return
 	}
 
-	if data.Error != "" {
-		writeJSON(w, http.StatusBadRequest, data.Error)
+	if failure != nil {
+		writeError(w, r, failure)
+		return
+	}
+	writeJSON(w, http.StatusOK, toResponse(pkg))
+}
+
+func (h *Handler) ListPackages(w http.ResponseWriter, r *http.Request) {
+	summaries, err := h.service.ListPackages(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resp := make([]PackageSummaryResponse, len(summaries))
+	for i, s := range summaries {
+		resp[i] = PackageSummaryResponse{
+			Name: s.PackageRef.Name,
+			Version: s.PackageRef.Version,
+			Health: s.Health,
+			Dependencies: s.Dependencies,
+			Unscored: len(s.Unscored),
+			Advisories: s.Advisories,
+			Deprecated: len(s.Deprecated),
+			Failed: len(s.Failed),
+			LastUpdatedAt: s.LastUpdatedAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) RetryFailed(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	pkg, err := h.service.RetryFailed(r.Context(), name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		http.Redirect(w, r, "/deps/"+url.PathEscape(name), http.StatusSeeOther)
 		return
 	}
 	writeJSON(w, http.StatusOK, toResponse(pkg))
@@ -98,28 +281,120 @@ func (h *Handler) DeleteDeps(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 	err := h.service.DeleteDependenciesByName(r.Context(), name)
 	if err != nil {
-		writeJSON(w, http.StatusBadRequest, err)
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, http.StatusNoContent, nil)
 }
 
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	includeAcknowledged := r.URL.Query().Get("all") == "true"
+	alerts, err := h.service.ListAlerts(r.Context(), includeAcknowledged)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resp := make([]AlertResponse, len(alerts))
+	for i, a := range alerts {
+		resp[i] = AlertResponse{
+			ID: a.ID,
+			Package: a.PackageName,
+			Kind: string(a.Kind),
+			Dependency: a.Dependency,
+			Message: a.Message,
+			CreatedAt: a.CreatedAt,
+			AcknowledgedAt: a.AcknowledgedAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, domain.Errorf(domain.KindInvalidInput, "Invalid alert id"))
+		return
+	}
+	if err := h.service.AcknowledgeAlert(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
 
 func toResponse(pkg *domain.Package) DepsResponse {
 	nodes := make([]DependencyNode, len(pkg.Dependencies))
 	for i, n := range pkg.Dependencies {
-		nodes[i] = DependencyNode{
-			Name: n.Name,
-			Version: n.Version,
-			Relation: n.Relation,
-			Score: n.Score,
-		}
+		nodes[i] = toNodeResponse(n)
 	}
-	return DepsResponse{
+	resp := DepsResponse{
 		ID: pkg.ID,
 		Name: pkg.PackageRef.Name,
 		Version: pkg.PackageRef.Version,
+		Health: pkg.Health,
 		Dependencies: nodes,
+		LastUpdatedAt: pkg.LastUpdatedAt,
+	}
+	if pkg.Page != nil {
+		resp.Total = pkg.Page.Total
+		resp.NextCursor = pkg.Page.NextCursor
+	}
+	if pkg.Refresh != nil {
+		resp.Refresh = &RefreshResponse{
+			Reused: pkg.Refresh.Reused,
+			Enriched: pkg.Refresh.Enriched,
+			CallsSaved: pkg.Refresh.CallsSaved,
+		}
+	}
+	return resp
+}
+
+func toNodeResponse(n domain.DependencyNode) DependencyNode {
+	node := DependencyNode{
+		Name: n.Name,
+		Version: n.Version,
+		Relation: n.Relation,
+		Score: n.Score,
+		Advisories: n.Advisories,
+		Severity: n.Severity,
+		License: n.License,
+		Depth: n.Depth,
+		FanIn: n.FanIn,
+		Project: n.ProjectKey,
+		LatestVersion: n.LatestVersion,
+		Deprecated: n.Deprecated,
+		DeprecationReason: n.DeprecationReason,
+		PublishedAt: n.PublishedAt,
+		LatestReleaseAt: n.LatestReleaseAt,
+		DaysSinceRelease: domain.DaysSince(n.PublishedAt, time.Now()),
+		DaysSinceLatestRelease: domain.DaysSince(n.LatestReleaseAt, time.Now()),
+		Status: string(n.Status),
+		StatusMessage: n.StatusMessage,
+	}
+	if n.Project != nil {
+		node.Source = toProjectResponse(n.Project)
+	}
+	if n.LatestVersion != "" {
+		node.Outdated = n.Behind.Outdated()
+		node.Behind = &VersionLagResponse{Major: n.Behind.Major, Minor: n.Behind.Minor, Patch: n.Behind.Patch}
+	}
+	return node
+}
+
+func toProjectResponse(p *domain.Project) *ProjectResponse {
+	return &ProjectResponse{
+		URL: domain.SourceURL(p.Key),
+		Description: p.Description,
+		Homepage: p.Homepage,
+		License: p.License,
+		Stars: p.Stars,
+		Forks: p.Forks,
+		OpenIssues: p.OpenIssues,
+		OSSFuzz: p.OSSFuzz,
 	}
 }
 
@@ -0,0 +1,263 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev/depsdevtest"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/service"
+)
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(ctx context.Context, events []domain.Event) {}
+
+func newTestRouter(t *testing.T) (*http.ServeMux, *memory.Repository) {
+	server := depsdevtest.NewServer(3)
+	t.Cleanup(server.Close)
+	repo := memory.NewRepository()
+	svc := service.NewDependencyService(repo, repo, repo, depsdev.NewClient(server.Client()), discardPublisher{}, service.Config{})
+	return NewRouter(svc, Config{}), repo
+}
+
+func serve(router http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func savePackage(t *testing.T, repo *memory.Repository, name string, scores ...float64) *domain.Package {
+	pkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: name, Version: "1.0.0"},
+		LastUpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for i, score := range scores {
+		dependency := fmt.Sprintf("%s-dep-%d", name, i)
+		key := "github.com/example/" + dependency
+		pkg.Dependencies = append(pkg.Dependencies, domain.DependencyNode{
+			Name: dependency,
+			Version: "1.0.0",
+			Relation: "DIRECT",
+			ProjectKey: key,
+			Project: &domain.Project{Key: key, Scorecard: domain.Scorecard{Score: score, Date: pkg.LastUpdatedAt}},
+		})
+	}
+	if err := repo.Save(context.Background(), pkg, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	return pkg
+}
+
+type failingRepository struct {
+	*memory.Repository
+	alerts bool
+	packages bool
+}
+
+func (r failingRepository) ListAlerts(ctx context.Context, includeAcknowledged bool, since time.Time) ([]domain.Alert, error) {
+	if r.alerts {
+		return nil, errors.New("Query alerts error: database is locked")
+	}
+	return r.Repository.ListAlerts(ctx, includeAcknowledged, since)
+}
+
+func (r failingRepository) List(ctx context.Context) ([]*domain.Package, error) {
+	if r.packages {
+		return nil, errors.New("Query packages error: database is locked")
+	}
+	return r.Repository.List(ctx)
+}
+
+func TestGetDepsHTMLStorageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		repo failingRepository
+	}{
+		{name: "alerts", repo: failingRepository{alerts: true}},
+		{name: "packages", repo: failingRepository{packages: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewRepository()
+			savePackage(t, repo, "app", 5)
+			tt.repo.Repository = repo
+			svc := service.NewDependencyService(tt.repo, tt.repo, repo, depsdev.NewClient(http.DefaultClient), discardPublisher{}, service.Config{})
+			router := NewRouter(svc, Config{})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/deps/app", nil)
+			r.Header.Set("Accept", "text/html")
+			router.ServeHTTP(w, r)
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("Got %d, expected storage failure to be reported", w.Code)
+			}
+			if problem := decodeProblem(t, w); problem.Detail != internalErrorDetail {
+				t.Errorf("Got detail %q, expected generic detail", problem.Detail)
+			}
+		})
+	}
+}
+
+func TestGetDepsPaging(t *testing.T) {
+	router, repo := newTestRouter(t)
+	savePackage(t, repo, "app", 9, 8, 7, 6, 5)
+
+	tests := []struct {
+		name string
+		query string
+		status int
+		nodes int
+	}{
+		{name: "no limit", query: "", status: http.StatusOK, nodes: 5},
+		{name: "lower bound", query: "?limit=1", status: http.StatusOK, nodes: 1},
+		{name: "upper bound", query: "?limit=1000", status: http.StatusOK, nodes: 5},
+		{name: "zero", query: "?limit=0", status: http.StatusBadRequest},
+		{name: "above maximum", query: "?limit=1001", status: http.StatusBadRequest},
+		{name: "not a number", query: "?limit=ten", status: http.StatusBadRequest},
+		{name: "bad cursor", query: "?limit=2&cursor=garbage", status: http.StatusBadRequest},
+		{name: "unknown sort", query: "?sort=secret", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/deps/app"+tt.query)
+			if w.Code != tt.status {
+				t.Fatalf("Got status %d, expected %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var response DepsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if len(response.Dependencies) != tt.nodes {
+				t.Errorf("Got %d nodes, expected %d", len(response.Dependencies), tt.nodes)
+			}
+		})
+	}
+}
+
+func TestGetDepsDefaultPageSize(t *testing.T) {
+	router, repo := newTestRouter(t)
+	scores := make([]float64, defaultPageSize+20)
+	for i := range scores {
+		scores[i] = 5
+	}
+	savePackage(t, repo, "app", scores...)
+
+	var response DepsResponse
+	if err := json.NewDecoder(serve(router, http.MethodGet, "/deps/app").Body).Decode(&response); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if len(response.Dependencies) != defaultPageSize || response.Total != len(scores) || response.NextCursor == "" {
+		t.Fatalf("Got %d of %d nodes (cursor %q), expected first page of %d", len(response.Dependencies), response.Total, response.NextCursor, defaultPageSize)
+	}
+
+	var next DepsResponse
+	if err := json.NewDecoder(serve(router, http.MethodGet, "/deps/app?cursor="+url.QueryEscape(response.NextCursor)).Body).Decode(&next); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if len(next.Dependencies) != len(scores)-defaultPageSize || next.NextCursor != "" {
+		t.Errorf("Got %d nodes (cursor %q), expected the remaining %d", len(next.Dependencies), next.NextCursor, len(scores)-defaultPageSize)
+	}
+}
+
+func TestGetDepsFilters(t *testing.T) {
+	router, repo := newTestRouter(t)
+	if err := repo.Save(context.Background(), &domain.Package{
+		PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+		LastUpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Dependencies: []domain.DependencyNode{
+			{Name: "app", Version: "1.0.0", Relation: "SELF"},
+			{Name: "qs", Version: "6.14.0", Relation: "DIRECT", Depth: 1, LatestVersion: "6.15.0", Behind: domain.VersionLag{Minor: 1}},
+			{Name: "debug", Version: "2.6.9", Relation: "DIRECT", Depth: 1, LatestVersion: "4.4.0", Behind: domain.VersionLag{Major: 2},
+				Deprecated: true, DeprecationReason: "Use debug 4"},
+		},
+	}, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		query string
+		status int
+		expected []string
+	}{
+		{name: "outdated", query: "?outdated=true", status: http.StatusOK, expected: []string{"qs", "debug"}},
+		{name: "up to date", query: "?outdated=false", status: http.StatusOK, expected: []string{"app"}},
+		{name: "deprecated", query: "?deprecated=true", status: http.StatusOK, expected: []string{"debug"}},
+		{name: "not deprecated and outdated", query: "?deprecated=false&outdated=true", status: http.StatusOK, expected: []string{"qs"}},
+		{name: "invalid outdated", query: "?outdated=maybe", status: http.StatusBadRequest},
+		{name: "invalid deprecated", query: "?deprecated=yes", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/deps/app"+tt.query)
+			if w.Code != tt.status {
+				t.Fatalf("Got status %d, expected %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var response DepsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			var names []string
+			for _, dependency := range response.Dependencies {
+				names = append(names, dependency.Name)
+			}
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("Got %v, expected %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetDepsFreshness(t *testing.T) {
+	router, repo := newTestRouter(t)
+	published := time.Now().UTC().Add(-400 * 24 * time.Hour)
+	latest := time.Now().UTC().Add(-10*24*time.Hour - time.Hour)
+	if err := repo.Save(context.Background(), &domain.Package{
+		PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+		LastUpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Dependencies: []domain.DependencyNode{
+			{Name: "app", Version: "1.0.0", Relation: "SELF"},
+			{Name: "qs", Version: "6.14.0", Relation: "DIRECT", Depth: 1, PublishedAt: &published, LatestReleaseAt: &latest},
+		},
+	}, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	w := serve(router, http.MethodGet, "/deps/app?sort=-depth")
+	var response DepsResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if len(response.Dependencies) != 2 {
+		t.Fatalf("Got %d dependencies, expected 2", len(response.Dependencies))
+	}
+
+	qs, app := response.Dependencies[0], response.Dependencies[1]
+	if qs.PublishedAt == nil || !qs.PublishedAt.Equal(published) || qs.DaysSinceRelease == nil || *qs.DaysSinceRelease != 400 {
+		t.Errorf("Got published %v (%v days), expected %v (400 days)", qs.PublishedAt, qs.DaysSinceRelease, published)
+	}
+	if qs.LatestReleaseAt == nil || qs.DaysSinceLatestRelease == nil || *qs.DaysSinceLatestRelease != 10 {
+		t.Errorf("Got latest release %v (%v days), expected 10 days", qs.LatestReleaseAt, qs.DaysSinceLatestRelease)
+	}
+	if app.PublishedAt != nil || app.DaysSinceRelease != nil || app.DaysSinceLatestRelease != nil {
+		t.Errorf("Got %+v, expected no freshness for unknown dates", app)
+	}
+}
@@ -10,7 +10,37 @@ type DepsResponse struct {
 	ID int64	`json:"id"`
 	Name	string `json:"name"`
 	Version string	`json:"version"`
+	Health *float64 `json:"health,omitempty"`
 	Dependencies []DependencyNode `json:"dependencies"`
+	Total int `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	LastUpdatedAt time.Time `json:"last_updated_at"`
+	Refresh *RefreshResponse `json:"refresh,omitempty"`
+}
+
+type ProblemResponse struct {
+	Type string `json:"type"`
+	Title string `json:"title"`
+	Status int `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+type RefreshResponse struct {
+	Reused int `json:"reused"`
+	Enriched int `json:"enriched"`
+	CallsSaved int `json:"calls_saved"`
+}
+
+type PackageSummaryResponse struct {
+	Name string `json:"name"`
+	Version string `json:"version"`
+	Health *float64 `json:"health,omitempty"`
+	Dependencies int `json:"dependencies"`
+	Unscored int `json:"unscored"`
+	Advisories int `json:"advisories"`
+	Deprecated int `json:"deprecated"`
+	Failed int `json:"failed"`
 	LastUpdatedAt time.Time `json:"last_updated_at"`
 }
 
@@ -19,5 +49,128 @@ type DependencyNode struct {
 	Version string `json:"version"`
 	Relation string `json:"relation"`
 	Score 	*float64 `json:"score,omitempty"`
+	Advisories []string `json:"advisories,omitempty"`
+	Severity *float64 `json:"severity,omitempty"`
+	License string `json:"license,omitempty"`
+	Depth int `json:"depth"`
+	FanIn int `json:"fan_in"`
+	Project string `json:"project,omitempty"`
+	Source *ProjectResponse `json:"source,omitempty"`
+	LatestVersion string `json:"latest_version,omitempty"`
+	Outdated bool `json:"outdated"`
+	Behind *VersionLagResponse `json:"behind,omitempty"`
+	Deprecated bool `json:"deprecated"`
+	DeprecationReason string `json:"deprecation_reason,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	LatestReleaseAt *time.Time `json:"latest_release_at,omitempty"`
+	DaysSinceRelease *int `json:"days_since_release,omitempty"`
+	DaysSinceLatestRelease *int `json:"days_since_latest_release,omitempty"`
+	Status string `json:"status,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+}
+
+type ProjectResponse struct {
+	URL string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Homepage string `json:"homepage,omitempty"`
+	License string `json:"license,omitempty"`
+	Stars int `json:"stars"`
+	Forks int `json:"forks"`
+	OpenIssues int `json:"open_issues"`
+	OSSFuzz bool `json:"oss_fuzz"`
+}
+
+type VersionLagResponse struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+}
+
+type DependencyDetailResponse struct {
+	Package string `json:"package"`
+	Name string `json:"name"`
+	Versions []DependencyNode `json:"versions"`
+	Paths [][]string `json:"paths"`
+	ScoreHistory []ScorePointResponse `json:"score_history"`
+	Scorecard *ScorecardResponse `json:"scorecard,omitempty"`
+	Advisories []AdvisoryResponse `json:"advisories"`
+	SourceURL string `json:"source_url,omitempty"`
+	UsedBy []DependentResponse `json:"used_by"`
+}
+
+type ScorePointResponse struct {
+	TakenAt time.Time `json:"taken_at"`
+	Version string `json:"version"`
+	Score *float64 `json:"score,omitempty"`
+}
+
+type ScorecardResponse struct {
+	Score float64 `json:"score"`
+	Date time.Time `json:"date"`
+	Checks []ScorecardCheckResponse `json:"checks"`
 }
 
+type ScorecardCheckResponse struct {
+	Name string `json:"name"`
+	Score int `json:"score"`
+	Reason string `json:"reason"`
+	Documentation string `json:"documentation,omitempty"`
+}
+
+type AdvisoryResponse struct {
+	ID string `json:"id"`
+	Title string `json:"title,omitempty"`
+	CVSS3Score float64 `json:"cvss3_score,omitempty"`
+}
+
+type DependentsResponse struct {
+	Dependency string `json:"dependency"`
+	Version string `json:"version,omitempty"`
+	Dependents []DependentResponse `json:"dependents"`
+}
+
+type DependentResponse struct {
+	Package string `json:"package"`
+	PackageVersion string `json:"package_version"`
+	Version string `json:"version"`
+	Relation string `json:"relation"`
+	Depth int `json:"depth"`
+}
+
+type AlertResponse struct {
+	ID int64 `json:"id"`
+	Package string `json:"package"`
+	Kind string `json:"kind"`
+	Dependency string `json:"dependency"`
+	Message string `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+
+
+type WebhookRequest struct {
+	URL string `json:"url"`
+	Secret string `json:"secret"`
+	Events []string `json:"events"`
+	Format string `json:"format"`
+}
+
+type WebhookResponse struct {
+	ID int64 `json:"id"`
+	URL string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+	Events []string `json:"events"`
+	Format string `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID int64 `json:"id"`
+	Event string `json:"event"`
+	Attempt int `json:"attempt"`
+	StatusCode int `json:"status_code"`
+	Error string `json:"error,omitempty"`
+	Success bool `json:"success"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
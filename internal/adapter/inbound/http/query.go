@@ -0,0 +1,208 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+var operatorAliases = map[string]domain.Operator{
+	"=":  domain.FilterEq,
+	"!=": domain.FilterNe,
+	"<":  domain.FilterLt,
+	"<=": domain.FilterLte,
+	">":  domain.FilterGt,
+	">=": domain.FilterGte,
+}
+
+var operators = []domain.Operator{
+	domain.FilterEq,
+	domain.FilterNe,
+	domain.FilterLt,
+	domain.FilterLte,
+	domain.FilterGt,
+	domain.FilterGte,
+	domain.FilterIn,
+	domain.FilterPrefix,
+	domain.FilterLike,
+	domain.FilterIsNull,
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+func (t queryToken) is(keyword string) bool {
+	return !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func parseQuery(query string) (domain.Filter, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	if len(tokens) == 0 {
+		return domain.Filter{}, fmt.Errorf("Empty query")
+	}
+
+	p := &queryParser{tokens: tokens}
+	filter, err := p.expr()
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return domain.Filter{}, fmt.Errorf("Unexpected %q in query", p.tokens[p.pos].text)
+	}
+	return filter, nil
+}
+
+func tokenizeQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, queryToken{text: string(c)})
+			i++
+		case c == '"':
+			var value strings.Builder
+			i++
+			for ; i < len(query) && query[i] != '"'; i++ {
+				if query[i] == '\\' && i+1 < len(query) {
+					i++
+				}
+				value.WriteByte(query[i])
+			}
+			if i >= len(query) {
+				return nil, fmt.Errorf("Unterminated quoted value in query")
+			}
+			tokens = append(tokens, queryToken{text: value.String(), quoted: true})
+			i++
+		default:
+			start := i
+			for i < len(query) && !strings.ContainsRune(" \t\n\r()\"", rune(query[i])) {
+				i++
+			}
+			tokens = append(tokens, queryToken{text: query[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *queryParser) next() (queryToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		return queryToken{}, fmt.Errorf("Unexpected end of query")
+	}
+	p.pos++
+	return token, nil
+}
+
+func (p *queryParser) expr() (domain.Filter, error) {
+	return p.group(domain.LogicOr, "or", p.term)
+}
+
+func (p *queryParser) term() (domain.Filter, error) {
+	return p.group(domain.LogicAnd, "and", p.factor)
+}
+
+func (p *queryParser) group(logic domain.Logic, keyword string, operand func() (domain.Filter, error)) (domain.Filter, error) {
+	first, err := operand()
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	filters := []domain.Filter{first}
+	for {
+		token, ok := p.peek()
+		if !ok || !token.is(keyword) {
+			break
+		}
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return domain.Filter{}, err
+		}
+		filters = append(filters, next)
+	}
+	if len(filters) == 1 {
+		return first, nil
+	}
+	return domain.Filter{Logic: logic, Filters: filters}, nil
+}
+
+func (p *queryParser) factor() (domain.Filter, error) {
+	token, err := p.next()
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	if token.is("(") {
+		filter, err := p.expr()
+		if err != nil {
+			return domain.Filter{}, err
+		}
+		closing, err := p.next()
+		if err != nil || !closing.is(")") {
+			return domain.Filter{}, fmt.Errorf("Missing closing parenthesis in query")
+		}
+		return filter, nil
+	}
+	if token.quoted || token.is(")") || token.is("and") || token.is("or") {
+		return domain.Filter{}, fmt.Errorf("Expected column name, got %q", token.text)
+	}
+
+	filter := domain.Filter{Column: strings.ToLower(token.text)}
+	opToken, err := p.next()
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	if filter.Operator, err = parseOperator(opToken); err != nil {
+		return domain.Filter{}, err
+	}
+
+	if filter.Operator == domain.FilterIsNull {
+		if next, ok := p.peek(); ok && (next.is("true") || next.is("false")) {
+			filter.Value = strings.ToLower(next.text)
+			p.pos++
+		}
+		return filter, nil
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	if !value.quoted && (value.is("(") || value.is(")")) {
+		return domain.Filter{}, fmt.Errorf("Expected value, got %q", value.text)
+	}
+	filter.Value = value.text
+	return filter, nil
+}
+
+func parseOperator(token queryToken) (domain.Operator, error) {
+	if !token.quoted {
+		if op, ok := operatorAliases[token.text]; ok {
+			return op, nil
+		}
+		for _, op := range operators {
+			if token.is(string(op)) {
+				return op, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Unknown operator error: %q", token.text)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected domain.Filter
+		Error    string
+	}{
+		{
+			name:     "single condition",
+			query:    "score lt 4",
+			expected: domain.Filter{Column: "score", Operator: domain.FilterLt, Value: "4"},
+		},
+		{
+			name:  "operator aliases and precedence",
+			query: `name = "body parser" OR score >= 7 AND relation in DIRECT,SELF`,
+			expected: domain.Filter{Logic: domain.LogicOr, Filters: []domain.Filter{
+				{Column: "name", Operator: domain.FilterEq, Value: "body parser"},
+				{Logic: domain.LogicAnd, Filters: []domain.Filter{
+					{Column: "score", Operator: domain.FilterGte, Value: "7"},
+					{Column: "relation", Operator: domain.FilterIn, Value: "DIRECT,SELF"},
+				}},
+			}},
+		},
+		{
+			name:  "parentheses and isnull",
+			query: "(license isnull or severity gt 7) and depth lte 2",
+			expected: domain.Filter{Logic: domain.LogicAnd, Filters: []domain.Filter{
+				{Logic: domain.LogicOr, Filters: []domain.Filter{
+					{Column: "license", Operator: domain.FilterIsNull},
+					{Column: "severity", Operator: domain.FilterGt, Value: "7"},
+				}},
+				{Column: "depth", Operator: domain.FilterLte, Value: "2"},
+			}},
+		},
+		{
+			name:  "quoted keyword is a value",
+			query: `name eq "or"`,
+			expected: domain.Filter{Column: "name", Operator: domain.FilterEq, Value: "or"},
+		},
+		{
+			name:  "missing value",
+			query: "score gt",
+			Error: "Unexpected end of query",
+		},
+		{
+			name:  "unknown operator",
+			query: "score between 1",
+			Error: "Unknown operator error: \"between\"",
+		},
+		{
+			name:  "unbalanced parentheses",
+			query: "(score gt 1",
+			Error: "Missing closing parenthesis in query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parseQuery(tt.query)
+			if tt.Error != "" {
+				if err == nil || err.Error() != tt.Error {
+					t.Fatalf("Got error %v, expected %s", err, tt.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error %s", err.Error())
+			}
+			if !reflect.DeepEqual(filter, tt.expected) {
+				t.Errorf("Got filter %+v, expected %+v", filter, tt.expected)
+			}
+		})
+	}
+}
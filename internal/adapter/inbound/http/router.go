@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"time"
 
 	"github.com/JCzapla/dep-dashboard/internal/domain"
 	"github.com/JCzapla/dep-dashboard/internal/port/inbound"
@@ -25,17 +26,21 @@ var templateFuncs = template.FuncMap{
 		return fmt.Sprintf("%.1f", *score*10)
 	},
 	"scoreBarColor": func(score *float64) string {
+		return "score-" + string(domain.ScoreBandOf(score))
+	},
+	"formatScore": func(score *float64) string {
 		if score == nil {
-			return "score-nil"
+			return "-"
 		}
-		switch {
-		case *score >= 7.5:
-			return "score-green"
-		case *score >= 4.0:
-			return "score-yellow"
-		default:	
-			return "score-red"
+		return fmt.Sprintf("%.1f", *score)
+	},
+	"sourceURL": domain.SourceURL,
+	"daysSince": func(t *time.Time) string {
+		days := domain.DaysSince(t, time.Now())
+		if days == nil {
+			return "-"
 		}
+		return fmt.Sprintf("%d days ago", *days)
 	},
 }
 
@@ -51,9 +56,7 @@ func NewRouter(service inbound.DependencyService, cfg Config) *http.ServeMux {
 		case http.MethodGet:
 			h.GetDeps(w, r)
 		default:
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte(`{"Error": "Method not allowed"}`))
+			writeMethodNotAllowed(w, r)
 		}
 	})
 	mux.HandleFunc("/deps/{name}", func(w http.ResponseWriter, r *http.Request) {
@@ -65,10 +68,22 @@ func NewRouter(service inbound.DependencyService, cfg Config) *http.ServeMux {
 		case http.MethodDelete:
 			h.DeleteDeps(w, r)
 		default:
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte(`{"Error": "Method not allowed"}`))
+			writeMethodNotAllowed(w, r)
 		}
 	})
+	mux.HandleFunc("GET /packages", h.ListPackages)
+	mux.HandleFunc("GET /badge/{file...}", h.Badge)
+	mux.HandleFunc("GET /feed.atom", h.Feed)
+	mux.HandleFunc("GET /deps/{name}/feed.atom", h.Feed)
+	mux.HandleFunc("POST /deps/{name}/retry", h.RetryFailed)
+	mux.HandleFunc("GET /deps/{name}/nodes/{dep...}", h.GetDependencyDetail)
+	mux.HandleFunc("GET /dependents", h.FindDependents)
+	mux.HandleFunc("GET /dependents/{dep...}", h.FindDependents)
+	mux.HandleFunc("GET /alerts", h.ListAlerts)
+	mux.HandleFunc("POST /alerts/{id}/ack", h.AcknowledgeAlert)
+	mux.HandleFunc("POST /webhooks", h.CreateWebhook)
+	mux.HandleFunc("GET /webhooks", h.ListWebhooks)
+	mux.HandleFunc("DELETE /webhooks/{id}", h.DeleteWebhook)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", h.ListWebhookDeliveries)
 	return mux
 }
\ No newline at end of file
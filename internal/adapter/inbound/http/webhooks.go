@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.Errorf(domain.KindInvalidInput, "Invalid request body"))
+		return
+	}
+
+	webhook := &domain.Webhook{
+		URL: req.URL,
+		Secret: req.Secret,
+		Format: domain.WebhookFormat(req.Format),
+	}
+	for _, event := range req.Events {
+		webhook.Events = append(webhook.Events, domain.EventType(event))
+	}
+	if err := h.service.CreateWebhook(r.Context(), webhook); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := toWebhookResponse(*webhook)
+	resp.Secret = webhook.Secret
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resp := make([]WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		resp[i] = toWebhookResponse(webhook)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, domain.Errorf(domain.KindInvalidInput, "Invalid webhook id"))
+		return
+	}
+	if err := h.service.DeleteWebhook(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, domain.Errorf(domain.KindInvalidInput, "Invalid webhook id"))
+		return
+	}
+	deliveries, err := h.service.ListWebhookDeliveries(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resp := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = WebhookDeliveryResponse{
+			ID: d.ID,
+			Event: string(d.Event),
+			Attempt: d.Attempt,
+			StatusCode: d.StatusCode,
+			Error: d.Error,
+			Success: d.Success,
+			DeliveredAt: d.DeliveredAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func toWebhookResponse(webhook domain.Webhook) WebhookResponse {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+	return WebhookResponse{
+		ID: webhook.ID,
+		URL: webhook.URL,
+		Events: events,
+		Format: string(webhook.Format),
+		CreatedAt: webhook.CreatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/port/inbound"
+)
+
+const digestRetry = 5 * time.Minute
+
+type Digest struct {
+	service inbound.DigestService
+}
+
+func NewDigest(service inbound.DigestService) *Digest {
+	return &Digest{service: service}
+}
+
+func (d *Digest) Run(ctx context.Context) {
+	failed := false
+	for {
+		wait := digestRetry
+		if !failed {
+			next, err := d.service.NextDigest(ctx)
+			if err != nil {
+				log.Printf("Digest schedule error: %v", err)
+			} else {
+				wait = time.Until(next)
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := d.service.SendDigest(ctx); err != nil {
+			log.Printf("Digest error: %v", err)
+			failed = true
+			continue
+		}
+		failed = false
+	}
+}
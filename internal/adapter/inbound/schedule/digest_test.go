@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+type fakeDigests struct {
+	mu sync.Mutex
+	period time.Duration
+	sentAt time.Time
+	sent int
+	failures int
+}
+
+func (f *fakeDigests) SendDigest(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("SMTP error: connection refused")
+	}
+	f.sent++
+	f.sentAt = time.Now()
+	return nil
+}
+
+func (f *fakeDigests) NextDigest(ctx context.Context) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sentAt.Add(f.period), nil
+}
+
+func (f *fakeDigests) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sent
+}
+
+func TestDigestSchedule(t *testing.T) {
+	tests := []struct {
+		name string
+		lastSent time.Duration
+		failures int
+		wait time.Duration
+		expected int
+	}{
+		{name: "not due yet", lastSent: -23 * time.Hour, wait: time.Hour - time.Second, expected: 0},
+		{name: "due after restart", lastSent: -23 * time.Hour, wait: time.Hour, expected: 1},
+		{name: "overdue", lastSent: -48 * time.Hour, wait: 0, expected: 1},
+		{name: "next period", lastSent: -23 * time.Hour, wait: 25 * time.Hour, expected: 2},
+		{name: "retry after failure", lastSent: -48 * time.Hour, failures: 1, wait: digestRetry, expected: 1},
+		{name: "no retry before delay", lastSent: -48 * time.Hour, failures: 1, wait: digestRetry - time.Second, expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				digests := &fakeDigests{period: 24 * time.Hour, sentAt: time.Now().Add(tt.lastSent), failures: tt.failures}
+				go NewDigest(digests).Run(ctx)
+
+				time.Sleep(tt.wait)
+				synctest.Wait()
+				if got := digests.count(); got != tt.expected {
+					t.Errorf("Got %d digests, expected %d", got, tt.expected)
+				}
+			})
+		})
+	}
+}
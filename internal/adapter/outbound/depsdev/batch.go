@@ -0,0 +1,203 @@
+package depsdev
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type versionKey struct {
+	System string `json:"system"`
+	Name string `json:"name"`
+	Version string `json:"version"`
+}
+
+type versionBatchRequest struct {
+	Requests []versionRequest `json:"requests"`
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+type versionRequest struct {
+	VersionKey versionKey `json:"versionKey"`
+}
+
+type versionBatchResponse struct {
+	Responses []struct {
+		Request versionRequest `json:"request"`
+		Version *getVersionResponse `json:"version"`
+	} `json:"responses"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (c *Client) FetchVersions(ctx context.Context, refs []domain.PackageRef) (map[domain.PackageRef]domain.VersionInfo, error) {
+	versions := make(map[domain.PackageRef]domain.VersionInfo, len(refs))
+	failed := make(map[domain.PackageRef]error)
+	fellBack := false
+	err := chunks(unique(refs), func(chunk []domain.PackageRef) error {
+		if err := c.versionBatch(ctx, chunk, versions); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			fellBack = true
+			maps.Copy(failed, fallback(chunk, versions, func(ref domain.PackageRef) (domain.VersionInfo, error) {
+				return c.FetchVersion(ctx, ref)
+			}))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Version batch error: %w", err)
+	}
+	if fellBack {
+		return versions, &domain.LookupError[domain.PackageRef]{Failed: failed}
+	}
+	return versions, nil
+}
+
+func (c *Client) versionBatch(ctx context.Context, chunk []domain.PackageRef, versions map[domain.PackageRef]domain.VersionInfo) error {
+	request := versionBatchRequest{Requests: make([]versionRequest, len(chunk))}
+	for i, ref := range chunk {
+		request.Requests[i] = versionRequest{VersionKey: versionKey{System: "NPM", Name: ref.Name, Version: ref.Version}}
+	}
+	for {
+		var result versionBatchResponse
+		if err := c.doRequest(ctx, http.MethodPost, baseURL+"/v3alpha/versionbatch", request, &result); err != nil {
+			return err
+		}
+		for _, response := range result.Responses {
+			if response.Version == nil {
+				continue
+			}
+			ref := domain.PackageRef{Name: response.Request.VersionKey.Name, Version: response.Request.VersionKey.Version}
+			versions[ref] = response.Version.info()
+		}
+		if result.NextPageToken == "" {
+			return nil
+		}
+		request.PageToken = result.NextPageToken
+	}
+}
+
+type projectBatchRequest struct {
+	Requests []projectRequest `json:"requests"`
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+type projectRequest struct {
+	ProjectKey struct {
+		ID string `json:"id"`
+	} `json:"projectKey"`
+}
+
+type projectBatchResponse struct {
+	Responses []struct {
+		Request projectRequest `json:"request"`
+		Project *getProjectResponse `json:"project"`
+	} `json:"responses"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (c *Client) FetchProjects(ctx context.Context, projectKeys []string) (map[string]domain.Project, error) {
+	projects := make(map[string]domain.Project, len(projectKeys))
+	failed := make(map[string]error)
+	fellBack := false
+	err := chunks(unique(projectKeys), func(chunk []string) error {
+		if err := c.projectBatch(ctx, chunk, projects); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			fellBack = true
+			maps.Copy(failed, fallback(chunk, projects, func(key string) (domain.Project, error) {
+				return c.FetchProject(ctx, key)
+			}))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Project batch error: %w", err)
+	}
+	if fellBack {
+		return projects, &domain.LookupError[string]{Failed: failed}
+	}
+	return projects, nil
+}
+
+func (c *Client) projectBatch(ctx context.Context, chunk []string, projects map[string]domain.Project) error {
+	request := projectBatchRequest{Requests: make([]projectRequest, len(chunk))}
+	for i, key := range chunk {
+		request.Requests[i].ProjectKey.ID = key
+	}
+	for {
+		var result projectBatchResponse
+		if err := c.doRequest(ctx, http.MethodPost, baseURL+"/v3alpha/projectbatch", request, &result); err != nil {
+			return err
+		}
+		for _, response := range result.Responses {
+			if response.Project == nil {
+				continue
+			}
+			key := response.Request.ProjectKey.ID
+			projects[key] = response.Project.project(key)
+		}
+		if result.NextPageToken == "" {
+			return nil
+		}
+		request.PageToken = result.NextPageToken
+	}
+}
+
+func fallback[K comparable, V any](keys []K, resolved map[K]V, fetch func(key K) (V, error)) map[K]error {
+	var pending []K
+	for _, key := range keys {
+		if _, ok := resolved[key]; !ok {
+			pending = append(pending, key)
+		}
+	}
+
+	failed := make(map[K]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	guard := make(chan struct{}, fallbackWorkers)
+	for _, key := range pending {
+		guard <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-guard }()
+			value, err := fetch(key)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				resolved[key] = value
+			case domain.KindOf(err) != domain.KindUpstreamNotFound:
+				failed[key] = err
+			}
+		})
+	}
+	wg.Wait()
+	return failed
+}
+
+func unique[K comparable](keys []K) []K {
+	seen := make(map[K]bool, len(keys))
+	var result []K
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func chunks[K any](keys []K, fetch func(chunk []K) error) error {
+	for start := 0; start < len(keys); start += batchLimit {
+		if err := fetch(keys[start:min(start+batchLimit, len(keys))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
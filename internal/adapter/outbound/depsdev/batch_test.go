@@ -0,0 +1,149 @@
+package depsdev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev/depsdevtest"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestFetchVersions(t *testing.T) {
+	server := depsdevtest.NewServer(0)
+	defer server.Close()
+	client := NewClient(server.Client())
+
+	var refs []domain.PackageRef
+	for i := range 250 {
+		refs = append(refs, domain.PackageRef{Name: fmt.Sprintf("dep-%d", i), Version: "1.0.0"})
+	}
+	refs = append(refs, refs[0], domain.PackageRef{Name: "missing", Version: "1.0.0"})
+
+	versions, err := client.FetchVersions(context.Background(), refs)
+	if err != nil {
+		t.Fatalf("FetchVersions failed: %v", err)
+	}
+	if len(versions) != 250 {
+		t.Errorf("Got %d versions, expected 250", len(versions))
+	}
+	if calls := server.Requests("versionbatch"); calls != 3 {
+		t.Errorf("Got %d batch requests, expected 3 pages", calls)
+	}
+	info := versions[domain.PackageRef{Name: "dep-10", Version: "1.0.0"}]
+	if info.ProjectKey != "github.com/example/dep-10" || len(info.Advisories) != 1 || info.Licenses[0] != "MIT" {
+		t.Errorf("Got %+v, expected project, advisory and license", info)
+	}
+	if _, ok := versions[domain.PackageRef{Name: "missing", Version: "1.0.0"}]; ok {
+		t.Errorf("Expected missing version to be absent")
+	}
+}
+
+func TestFetchProjects(t *testing.T) {
+	server := depsdevtest.NewServer(0)
+	defer server.Close()
+	client := NewClient(server.Client())
+
+	keys := []string{"github.com/expressjs/express", "github.com/ljharb/qs", "github.com/expressjs/express"}
+	projects, err := client.FetchProjects(context.Background(), keys)
+	if err != nil {
+		t.Fatalf("FetchProjects failed: %v", err)
+	}
+	if len(projects) != 2 || server.Requests("projectbatch") != 1 {
+		t.Errorf("Got %d projects in %d requests, expected 2 in 1", len(projects), server.Requests("projectbatch"))
+	}
+	project := projects["github.com/ljharb/qs"]
+	if project.Key != "github.com/ljharb/qs" || project.Stars != 42 || project.Scorecard.Score != 6.5 || project.FetchedAt.IsZero() {
+		t.Errorf("Got %+v, expected decoded project", project)
+	}
+}
+
+func TestBatchFallback(t *testing.T) {
+	ref := func(name string) domain.PackageRef {
+		return domain.PackageRef{Name: name, Version: "1.0.0"}
+	}
+	tests := []struct {
+		name string
+		refs []domain.PackageRef
+		resolved []string
+		failed []string
+		lookups int
+		fallback bool
+	}{
+		{
+			name: "batch succeeds",
+			refs: []domain.PackageRef{ref("dep-1"), ref("missing")},
+			resolved: []string{"dep-1"},
+		},
+		{
+			name: "failed batch falls back to members",
+			refs: []domain.PackageRef{ref("dep-1"), ref("unbatched-1"), ref("missing")},
+			resolved: []string{"dep-1", "unbatched-1"},
+			lookups: 3,
+			fallback: true,
+		},
+		{
+			name: "only failed members are reported",
+			refs: []domain.PackageRef{ref("dep-1"), ref("unavailable")},
+			resolved: []string{"dep-1"},
+			failed: []string{"unavailable"},
+			lookups: 2,
+			fallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := depsdevtest.NewServer(0)
+			defer server.Close()
+			client := NewClient(server.Client())
+
+			versions, err := client.FetchVersions(context.Background(), tt.refs)
+			for _, name := range tt.resolved {
+				if _, ok := versions[ref(name)]; !ok {
+					t.Errorf("Expected %s to be resolved, got %v", name, versions)
+				}
+			}
+			if len(versions) != len(tt.resolved) {
+				t.Errorf("Got %d versions, expected %d", len(versions), len(tt.resolved))
+			}
+
+			var lookup *domain.LookupError[domain.PackageRef]
+			if !tt.fallback {
+				if err != nil {
+					t.Errorf("Got error %v, expected none", err)
+				}
+			} else if !errors.As(err, &lookup) || len(lookup.Failed) != len(tt.failed) {
+				t.Errorf("Got error %v, expected fallback with failures of %v", err, tt.failed)
+			}
+			for _, name := range tt.failed {
+				if lookup == nil || lookup.Failed[ref(name)] == nil {
+					t.Errorf("Expected lookup failure of %s, got %v", name, err)
+				}
+			}
+			if calls := server.Requests("version"); calls != tt.lookups {
+				t.Errorf("Got %d per-version lookups, expected %d", calls, tt.lookups)
+			}
+		})
+	}
+}
+
+func TestProjectBatchFallback(t *testing.T) {
+	server := depsdevtest.NewServer(0)
+	defer server.Close()
+	client := NewClient(server.Client())
+
+	keys := []string{"github.com/example/qs", "github.com/example/unbatched", "github.com/example/unavailable"}
+	projects, err := client.FetchProjects(context.Background(), keys)
+	if len(projects) != 2 || projects["github.com/example/unbatched"].Key == "" {
+		t.Errorf("Got %v, expected members of failed batch to be fetched one by one", projects)
+	}
+	var lookup *domain.LookupError[string]
+	if !errors.As(err, &lookup) || len(lookup.Failed) != 1 || lookup.Failed["github.com/example/unavailable"] == nil {
+		t.Errorf("Got error %v, expected only unavailable project to fail", err)
+	}
+	if server.Requests("projectbatch") != 1 || server.Requests("project") != 3 {
+		t.Errorf("Got %d batch and %d single requests, expected 1 and 3", server.Requests("projectbatch"), server.Requests("project"))
+	}
+}
@@ -1,17 +1,25 @@
 package depsdev
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
 )
 
 
-const baseURL = "https://api.deps.dev/v3"
+const (
+	baseURL = "https://api.deps.dev"
+	batchLimit = outbound.BatchLimit
+	fallbackWorkers = 10
+)
 
 type Client struct {
 	http *http.Client
@@ -26,28 +34,43 @@ type getPackageResponse struct {
 		VersionKey struct {
 			Version string
 		} `json:"versionKey"`
+		PublishedAt time.Time `json:"publishedAt"`
 		IsDefault bool `json:"isDefault"`
 	} `json:"versions"`
 }
 
 
 func (c *Client) FetchDefaultVersion(ctx context.Context, name string) (string, error) {
-	apiURL := fmt.Sprintf("%s/systems/NPM/packages/%s",
+	info, err := c.FetchPackage(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return info.DefaultVersion, nil
+}
+
+func (c *Client) FetchPackage(ctx context.Context, name string) (domain.PackageInfo, error) {
+	apiURL := fmt.Sprintf("%s/v3/systems/NPM/packages/%s",
 		baseURL,
 		url.PathEscape(name),
 	)
 	var result getPackageResponse
-	if err := c.doRequest(ctx, http.MethodGet, apiURL, &result); err != nil {
-		return "", err
+	if err := c.doRequest(ctx, http.MethodGet, apiURL, nil, &result); err != nil {
+		return domain.PackageInfo{}, err
+	}
+	if len(result.Versions) == 0 {
+		return domain.PackageInfo{}, domain.Errorf(domain.KindUpstreamNotFound, "No versions of %s found on deps.dev", name)
 	}
-	
+
+	info := domain.PackageInfo{DefaultVersion: result.Versions[0].VersionKey.Version}
 	for _, version := range result.Versions {
 		if version.IsDefault {
-			return version.VersionKey.Version, nil
+			info.DefaultVersion = version.VersionKey.Version
+		}
+		if version.PublishedAt.After(info.LatestReleaseAt) {
+			info.LatestReleaseAt = version.PublishedAt
 		}
 	}
-
-	return result.Versions[0].VersionKey.Version, nil
+	return info, nil
 }
 
 type getVersionDependenciesResponse struct {
@@ -59,19 +82,23 @@ type getVersionDependenciesResponse struct {
 		} `json:"versionKey"`
 		Relation string `json:"relation"`
 	} `json:"nodes"`
+	Edges []struct {
+		FromNode int `json:"fromNode"`
+		ToNode int `json:"toNode"`
+	} `json:"edges"`
 }
 
 
-func (c *Client) FetchDependencies(ctx context.Context, ref domain.PackageRef) ([]domain.DependencyNode, error) {
-	apiURL := fmt.Sprintf("%s/systems/NPM/packages/%s/versions/%s:dependencies",
+func (c *Client) FetchDependencies(ctx context.Context, ref domain.PackageRef) ([]domain.DependencyNode, []domain.Edge, error) {
+	apiURL := fmt.Sprintf("%s/v3/systems/NPM/packages/%s/versions/%s:dependencies",
 		baseURL,
 		url.PathEscape(ref.Name),
 		url.PathEscape(ref.Version),
 	)
 
 	var result getVersionDependenciesResponse
-	if err := c.doRequest(ctx, http.MethodGet, apiURL, &result); err != nil {
-		return nil, err
+	if err := c.doRequest(ctx, http.MethodGet, apiURL, nil, &result); err != nil {
+		return nil, nil, err
 	}
 
 	var nodes []domain.DependencyNode
@@ -80,13 +107,55 @@ func (c *Client) FetchDependencies(ctx context.Context, ref domain.PackageRef) (
 			Name: n.VersionKey.Name,
 			Version: n.VersionKey.Version,
 			Relation: n.Relation,
+			Depth: -1,
+		})
+	}
+
+	children := make(map[int][]int)
+	var edges []domain.Edge
+	for _, e := range result.Edges {
+		if e.FromNode < 0 || e.FromNode >= len(nodes) || e.ToNode < 0 || e.ToNode >= len(nodes) {
+			continue
+		}
+		children[e.FromNode] = append(children[e.FromNode], e.ToNode)
+		nodes[e.ToNode].FanIn++
+		edges = append(edges, domain.Edge{
+			From: domain.PackageRef{Name: nodes[e.FromNode].Name, Version: nodes[e.FromNode].Version},
+			To: domain.PackageRef{Name: nodes[e.ToNode].Name, Version: nodes[e.ToNode].Version},
 		})
 	}
 
-	return nodes, nil
+	if len(nodes) > 0 {
+		nodes[0].Depth = 0
+		queue := []int{0}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, child := range children[current] {
+				if nodes[child].Depth == -1 {
+					nodes[child].Depth = nodes[current].Depth + 1
+					queue = append(queue, child)
+				}
+			}
+		}
+	}
+	for i := range nodes {
+		if nodes[i].Depth == -1 {
+			nodes[i].Depth = 1
+		}
+	}
+
+	return nodes, edges, nil
 }
 
 type getVersionResponse struct {
+	Licenses []string `json:"licenses"`
+	PublishedAt time.Time `json:"publishedAt"`
+	IsDeprecated bool `json:"isDeprecated"`
+	DeprecatedReason string `json:"deprecatedReason"`
+	AdvisoryKeys []struct {
+		ID string `json:"id"`
+	} `json:"advisoryKeys"`
 	RelatedProjects []struct {
 		ProjectKey struct {
 			ID string `json:"id"`
@@ -94,60 +163,152 @@ type getVersionResponse struct {
 	} `json:"relatedProjects"`
 }
 
-func (c *Client) FetchProjectKey(ctx context.Context, ref domain.PackageRef) (string, error) {
-	apiURL := fmt.Sprintf("%s/systems/NPM/packages/%s/versions/%s",
+func (c *Client) FetchVersion(ctx context.Context, ref domain.PackageRef) (domain.VersionInfo, error) {
+	apiURL := fmt.Sprintf("%s/v3/systems/NPM/packages/%s/versions/%s",
 		baseURL,
 		url.PathEscape(ref.Name),
 		url.PathEscape(ref.Version),
 	)
 	var result getVersionResponse
-	if err := c.doRequest(ctx, http.MethodGet, apiURL, &result); err != nil {
-		return "", err
+	if err := c.doRequest(ctx, http.MethodGet, apiURL, nil, &result); err != nil {
+		return domain.VersionInfo{}, err
+	}
+	return result.info(), nil
+}
+
+func (result getVersionResponse) info() domain.VersionInfo {
+	info := domain.VersionInfo{
+		Licenses: result.Licenses,
+		Deprecated: result.IsDeprecated,
+		DeprecationReason: result.DeprecatedReason,
+		PublishedAt: result.PublishedAt,
+	}
+	for _, advisory := range result.AdvisoryKeys {
+		info.Advisories = append(info.Advisories, advisory.ID)
 	}
+	if len(result.RelatedProjects) > 0 {
+		info.ProjectKey = result.RelatedProjects[0].ProjectKey.ID
+	}
+	return info
+}
 
-	if len(result.RelatedProjects) == 0 {
-		return "", nil
+type getAdvisoryResponse struct {
+	Title string `json:"title"`
+	CVSS3Score float64 `json:"cvss3Score"`
+}
+
+func (c *Client) FetchAdvisory(ctx context.Context, id string) (domain.Advisory, error) {
+	apiURL := fmt.Sprintf("%s/v3/advisories/%s",
+		baseURL,
+		url.PathEscape(id),
+	)
+	var result getAdvisoryResponse
+	if err := c.doRequest(ctx, http.MethodGet, apiURL, nil, &result); err != nil {
+		return domain.Advisory{}, err
 	}
-	return result.RelatedProjects[0].ProjectKey.ID,nil
+
+	return domain.Advisory{
+		ID: id,
+		Title: result.Title,
+		CVSS3Score: result.CVSS3Score,
+	}, nil
 }
 
 type getProjectResponse struct {
+	OpenIssuesCount int `json:"openIssuesCount"`
+	StarsCount int `json:"starsCount"`
+	ForksCount int `json:"forksCount"`
+	License string `json:"license"`
+	Description string `json:"description"`
+	Homepage string `json:"homepage"`
+	OSSFuzz *struct {
+		LineCount int `json:"lineCount"`
+	} `json:"ossFuzz"`
 	Scorecard struct {
+		Date time.Time `json:"date"`
 		OverallScore float64 `json:"overallScore"`
+		Checks []struct {
+			Name string `json:"name"`
+			Documentation struct {
+				URL string `json:"url"`
+			} `json:"documentation"`
+			Score int `json:"score"`
+			Reason string `json:"reason"`
+		} `json:"checks"`
 	} `json:"scorecard"`
 }
 
-func (c *Client) FetchScore(ctx context.Context, projectKey string) (float64, error) {
-	apiURL := fmt.Sprintf("%s/projects/%s",
+func (c *Client) FetchProject(ctx context.Context, projectKey string) (domain.Project, error) {
+	apiURL := fmt.Sprintf("%s/v3/projects/%s",
 		baseURL,
 		url.PathEscape(projectKey),
 	)
 	var result getProjectResponse
-	if err := c.doRequest(ctx, http.MethodGet, apiURL, &result); err != nil {
-		return 0.0, err
+	if err := c.doRequest(ctx, http.MethodGet, apiURL, nil, &result); err != nil {
+		return domain.Project{}, err
 	}
+	return result.project(projectKey), nil
+}
 
-	return result.Scorecard.OverallScore, nil
+func (result getProjectResponse) project(projectKey string) domain.Project {
+	project := domain.Project{
+		Key: projectKey,
+		Description: result.Description,
+		Homepage: result.Homepage,
+		License: result.License,
+		Stars: result.StarsCount,
+		Forks: result.ForksCount,
+		OpenIssues: result.OpenIssuesCount,
+		OSSFuzz: result.OSSFuzz != nil,
+		Scorecard: domain.Scorecard{
+			Score: result.Scorecard.OverallScore,
+			Date: result.Scorecard.Date,
+		},
+		FetchedAt: time.Now().UTC(),
+	}
+	for _, check := range result.Scorecard.Checks {
+		project.Scorecard.Checks = append(project.Scorecard.Checks, domain.ScorecardCheck{
+			Name: check.Name,
+			Score: check.Score,
+			Reason: check.Reason,
+			Documentation: check.Documentation.URL,
+		})
+	}
+	return project
 }
 
-func (c *Client) doRequest(ctx context.Context, method string, url string, result any) error{
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+func (c *Client) doRequest(ctx context.Context, method string, url string, body any, result any) error{
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("Error encoding request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
 		return fmt.Errorf("Error building request: %w", err)
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return fmt.Errorf("Error calling deps.dev API: %w", err)
+		return domain.Errorf(domain.KindUpstreamUnavailable, "Error calling deps.dev API: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.Errorf(domain.KindUpstreamNotFound, "Error from deps.dev: %d", resp.StatusCode)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Error from deps.dev: %d", resp.StatusCode)
+		return domain.Errorf(domain.KindUpstreamUnavailable, "Error from deps.dev: %d", resp.StatusCode)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return fmt.Errorf("Error decoding response: %w", err)
+		return domain.Errorf(domain.KindUpstreamUnavailable, "Error decoding response: %w", err)
 	}
 
 	return nil
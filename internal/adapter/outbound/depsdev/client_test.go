@@ -0,0 +1,56 @@
+package depsdev
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev/depsdevtest"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestFetchPackageErrorKinds(t *testing.T) {
+	server := depsdevtest.NewServer(0)
+	defer server.Close()
+	client := NewClient(server.Client())
+
+	tests := []struct {
+		name string
+		expected domain.ErrorKind
+	}{
+		{name: "express", expected: ""},
+		{name: "missing", expected: domain.KindUpstreamNotFound},
+		{name: "unavailable", expected: domain.KindUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.FetchPackage(context.Background(), tt.name)
+			if kind := domain.KindOf(err); kind != tt.expected {
+				t.Errorf("Got kind %q (%v), expected %q", kind, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFetchPackage(t *testing.T) {
+	server := depsdevtest.NewServer(0)
+	defer server.Close()
+	client := NewClient(server.Client())
+
+	info, err := client.FetchPackage(context.Background(), "express")
+	if err != nil {
+		t.Fatalf("FetchPackage failed: %v", err)
+	}
+	expected := domain.PackageInfo{
+		DefaultVersion: "2.0.0",
+		LatestReleaseAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if !info.LatestReleaseAt.Equal(expected.LatestReleaseAt) || info.DefaultVersion != expected.DefaultVersion {
+		t.Errorf("Got %+v, expected default version and newest release of any version %+v", info, expected)
+	}
+
+	if _, err := client.FetchPackage(context.Background(), "empty"); err == nil {
+		t.Errorf("Got no error, expected package without versions to fail")
+	}
+}
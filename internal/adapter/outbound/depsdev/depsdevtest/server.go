@@ -0,0 +1,251 @@
+package depsdevtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const PageSize = 100
+
+type Server struct {
+	*httptest.Server
+	size int
+	mu sync.Mutex
+	requests map[string]int
+}
+
+func NewServer(size int) *Server {
+	s := &Server{size: size, requests: make(map[string]int)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v3/systems/NPM/packages/{name}", s.handlePackage)
+	mux.HandleFunc("GET /v3/systems/NPM/packages/{name}/versions/{version}", s.handleVersion)
+	mux.HandleFunc("GET /v3/advisories/{id}", s.handleAdvisory)
+	mux.HandleFunc("GET /v3/projects/{key}", s.handleProject)
+	mux.HandleFunc("POST /v3alpha/versionbatch", s.handleVersionBatch)
+	mux.HandleFunc("POST /v3alpha/projectbatch", s.handleProjectBatch)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: rewrite{target: target, next: s.Server.Client().Transport}}
+}
+
+func (s *Server) Requests(route string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[route]
+}
+
+func (s *Server) Total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, count := range s.requests {
+		total += count
+	}
+	return total
+}
+
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.requests)
+}
+
+func (s *Server) count(route string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[route]++
+}
+
+type rewrite struct {
+	target *url.URL
+	next http.RoundTripper
+}
+
+func (t rewrite) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
+
+func (s *Server) handlePackage(w http.ResponseWriter, r *http.Request) {
+	s.count("package")
+	switch r.PathValue("name") {
+	case "missing":
+		http.NotFound(w, r)
+		return
+	case "unavailable":
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	case "empty":
+		writeJSON(w, map[string]any{"versions": []map[string]any{}})
+		return
+	}
+	writeJSON(w, map[string]any{
+		"versions": []map[string]any{
+			{"versionKey": map[string]string{"version": "1.0.0"}, "publishedAt": "2026-01-01T00:00:00Z"},
+			{"versionKey": map[string]string{"version": "3.0.0-beta.1"}, "publishedAt": "2026-04-01T00:00:00Z"},
+			{"versionKey": map[string]string{"version": "2.0.0"}, "publishedAt": "2026-03-01T00:00:00Z", "isDefault": true},
+		},
+	})
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	version, dependencies := strings.CutSuffix(r.PathValue("version"), ":dependencies")
+	if dependencies {
+		s.count("dependencies")
+		writeJSON(w, s.graph(name, version))
+		return
+	}
+	s.count("version")
+	switch name {
+	case "missing":
+		http.NotFound(w, r)
+		return
+	case "unavailable":
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, versionInfo(name))
+}
+
+func (s *Server) handleAdvisory(w http.ResponseWriter, r *http.Request) {
+	s.count("advisory")
+	writeJSON(w, map[string]any{"title": "Advisory " + r.PathValue("id"), "cvss3Score": 7.5})
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	s.count("project")
+	if strings.HasSuffix(r.PathValue("key"), "/unavailable") {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, projectInfo(r.PathValue("key")))
+}
+
+func (s *Server) handleVersionBatch(w http.ResponseWriter, r *http.Request) {
+	s.count("versionbatch")
+	var request struct {
+		Requests []struct {
+			VersionKey struct {
+				Name string `json:"name"`
+				Version string `json:"version"`
+			} `json:"versionKey"`
+		} `json:"requests"`
+		PageToken string `json:"pageToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, item := range request.Requests {
+		if failsBatch(item.VersionKey.Name) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	start, end, next := page(len(request.Requests), request.PageToken)
+	responses := make([]map[string]any, 0, end-start)
+	for _, item := range request.Requests[start:end] {
+		response := map[string]any{"request": item}
+		if item.VersionKey.Name != "missing" {
+			response["version"] = versionInfo(item.VersionKey.Name)
+		}
+		responses = append(responses, response)
+	}
+	writeJSON(w, map[string]any{"responses": responses, "nextPageToken": next})
+}
+
+func (s *Server) handleProjectBatch(w http.ResponseWriter, r *http.Request) {
+	s.count("projectbatch")
+	var request struct {
+		Requests []struct {
+			ProjectKey struct {
+				ID string `json:"id"`
+			} `json:"projectKey"`
+		} `json:"requests"`
+		PageToken string `json:"pageToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, item := range request.Requests {
+		if failsBatch(item.ProjectKey.ID[strings.LastIndex(item.ProjectKey.ID, "/")+1:]) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	start, end, next := page(len(request.Requests), request.PageToken)
+	responses := make([]map[string]any, 0, end-start)
+	for _, item := range request.Requests[start:end] {
+		responses = append(responses, map[string]any{"request": item, "project": projectInfo(item.ProjectKey.ID)})
+	}
+	writeJSON(w, map[string]any{"responses": responses, "nextPageToken": next})
+}
+
+func failsBatch(name string) bool {
+	return name == "unavailable" || strings.HasPrefix(name, "unbatched")
+}
+
+func (s *Server) graph(root, version string) map[string]any {
+	nodes := []map[string]any{{"versionKey": map[string]string{"system": "NPM", "name": root, "version": version}, "relation": "SELF"}}
+	var edges []map[string]int
+	for i := range s.size {
+		name, version := Dependency(i, s.size)
+		nodes = append(nodes, map[string]any{"versionKey": map[string]string{"system": "NPM", "name": name, "version": version}, "relation": "INDIRECT"})
+		edges = append(edges, map[string]int{"fromNode": i / 2, "toNode": i + 1})
+	}
+	return map[string]any{"nodes": nodes, "edges": edges}
+}
+
+func Dependency(i, size int) (string, string) {
+	names := max(1, size/2)
+	return fmt.Sprintf("dep-%d", i%names), fmt.Sprintf("1.%d.0", i/names)
+}
+
+func versionInfo(name string) map[string]any {
+	info := map[string]any{
+		"licenses": []string{"MIT"},
+		"publishedAt": "2026-01-01T00:00:00Z",
+		"relatedProjects": []map[string]any{{"projectKey": map[string]string{"id": "github.com/example/" + name}}},
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(name, "dep-")); err == nil && n%10 == 0 {
+		info["advisoryKeys"] = []map[string]string{{"id": "GHSA-" + name}}
+	}
+	return info
+}
+
+func projectInfo(key string) map[string]any {
+	return map[string]any{
+		"starsCount": 42,
+		"description": "Project " + key,
+		"scorecard": map[string]any{"overallScore": 6.5, "date": "2026-02-01T00:00:00Z"},
+	}
+}
+
+func page(total int, token string) (int, int, string) {
+	start, _ := strconv.Atoi(token)
+	start = min(max(start, 0), total)
+	end := min(start+PageSize, total)
+	if end == total {
+		return start, end, ""
+	}
+	return start, end, strconv.Itoa(end)
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,157 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type Config struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+	Timeout  time.Duration
+}
+
+const defaultTimeout = 30 * time.Second
+
+//go:embed templates
+var templateFiles embed.FS
+
+var bandColors = map[domain.ScoreBand]string{
+	domain.ScoreBandGreen:  "green",
+	domain.ScoreBandYellow: "orange",
+	domain.ScoreBandRed:    "red",
+	domain.ScoreBandNil:    "gray",
+}
+
+var templateFuncs = template.FuncMap{
+	"formatScore": func(score *float64) string {
+		if score == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f", *score)
+	},
+	"scoreColor": func(score *float64) string {
+		return bandColors[domain.ScoreBandOf(score)]
+	},
+}
+
+type Sender struct {
+	config Config
+	tmpl   *template.Template
+}
+
+func NewSender(cfg Config) *Sender {
+	tmpl := template.Must(template.New("").Funcs(templateFuncs).ParseFS(templateFiles, "templates/*.html"))
+	return &Sender{config: cfg, tmpl: tmpl}
+}
+
+func (s *Sender) SendDigest(ctx context.Context, digest *domain.Digest) error {
+	if len(s.config.To) == 0 {
+		return fmt.Errorf("No digest recipients configured")
+	}
+
+	var body bytes.Buffer
+	if err := s.tmpl.ExecuteTemplate(&body, "digest.html", digest); err != nil {
+		return fmt.Errorf("Rendering digest error: %w", err)
+	}
+	subject := fmt.Sprintf("Dependency digest %s - %s",
+		digest.From.Format("2006-01-02"),
+		digest.To.Format("2006-01-02"),
+	)
+
+	if err := s.send(ctx, s.message(subject, body.Bytes())); err != nil {
+		return fmt.Errorf("SMTP error: %w", err)
+	}
+	return nil
+}
+
+func (s *Sender) send(ctx context.Context, msg []byte) error {
+	host, _, err := net.SplitHostPort(s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("Invalid SMTP address: %w", err)
+	}
+
+	timeout := s.config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.config.From); err != nil {
+		return err
+	}
+	for _, to := range s.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *Sender) message(subject string, body []byte) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.Write(body)
+	return msg.Bytes()
+}
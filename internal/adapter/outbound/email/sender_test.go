@@ -0,0 +1,148 @@
+package email
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func startSMTPServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "MAIL", "RCPT":
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				data, err := tp.ReadDotLines()
+				if err != nil {
+					return
+				}
+				messages <- strings.Join(data, "\n")
+				tp.PrintfLine("250 OK")
+			case "QUIT":
+				tp.PrintfLine("221 Bye")
+				return
+			default:
+				tp.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func TestSendDigest(t *testing.T) {
+	addr, messages := startSMTPServer(t)
+	sender := NewSender(Config{
+		Addr: addr,
+		From: "dashboard@example.com",
+		To: []string{"manager@example.com"},
+	})
+
+	score := 3.2
+	digest := &domain.Digest{
+		From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		To: time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC),
+		Packages: []domain.PackageSummary{{
+			PackageRef: domain.PackageRef{Name: "express", Version: "5.2.1"},
+			Dependencies: 2,
+			Scored: 1,
+			AverageScore: &score,
+			Unscored: []domain.DependencyNode{{Name: "unscored-dep", Version: "1.0.0", Relation: "DIRECT"}},
+		}},
+		NewAdvisories: []domain.Alert{{PackageName: "express", Message: "qs 6.14.0 is affected by advisory GHSA-xxxx"}},
+	}
+	if err := sender.SendDigest(context.Background(), digest); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	select {
+	case msg := <-messages:
+		for _, expected := range []string{
+			"Subject: Dependency digest 2026-01-01 - 2026-01-08",
+			"To: manager@example.com",
+			"Content-Type: text/html; charset=UTF-8",
+			"express",
+			"unscored-dep",
+			"GHSA-xxxx",
+			"3.2",
+		} {
+			if !strings.Contains(msg, expected) {
+				t.Errorf("Message does not contain %q", expected)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No message received")
+	}
+}
+
+func TestSendDigestHungServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { conn.Close() })
+	}()
+
+	tests := []struct {
+		name string
+		timeout time.Duration
+		ctxTimeout time.Duration
+	}{
+		{name: "context deadline", timeout: time.Minute, ctxTimeout: 100 * time.Millisecond},
+		{name: "configured timeout", timeout: 100 * time.Millisecond, ctxTimeout: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewSender(Config{
+				Addr: listener.Addr().String(),
+				From: "dashboard@example.com",
+				To: []string{"manager@example.com"},
+				Timeout: tt.timeout,
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := sender.SendDigest(ctx, &domain.Digest{})
+			if err == nil {
+				t.Fatal("Expected error from hung server")
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("SendDigest took %v", elapsed)
+			}
+		})
+	}
+}
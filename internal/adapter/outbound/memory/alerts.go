@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (r *Repository) SaveAlerts(ctx context.Context, alerts []domain.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range alerts {
+		alerts[i].ID = r.next("alerts")
+		alert := alerts[i]
+		alert.AcknowledgedAt = nil
+		r.alerts = append(r.alerts, alert)
+	}
+	return nil
+}
+
+func (r *Repository) ListAlerts(ctx context.Context, includeAcknowledged bool, since time.Time) ([]domain.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []domain.Alert
+	for _, alert := range r.alerts {
+		if alert.AcknowledgedAt != nil && !includeAcknowledged {
+			continue
+		}
+		if alert.CreatedAt.Before(since) {
+			continue
+		}
+		alert.AcknowledgedAt = clone(alert.AcknowledgedAt)
+		alerts = append(alerts, alert)
+	}
+	slices.SortFunc(alerts, func(a, b domain.Alert) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return alerts, nil
+}
+
+func (r *Repository) AcknowledgeAlert(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.alerts {
+		if r.alerts[i].ID != id {
+			continue
+		}
+		if r.alerts[i].AcknowledgedAt == nil {
+			now := time.Now().UTC()
+			r.alerts[i].AcknowledgedAt = &now
+		}
+		return nil
+	}
+	return domain.ErrAlertNotFound
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (r *Repository) GetProjects(ctx context.Context, keys []string) (map[string]domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make(map[string]domain.Project, len(keys))
+	for _, key := range keys {
+		if project, ok := r.projects[key]; ok {
+			project.Scorecard.Checks = slices.Clone(project.Scorecard.Checks)
+			projects[key] = project
+		}
+	}
+	return projects, nil
+}
+
+func (r *Repository) GetAdvisories(ctx context.Context, ids []string) (map[string]domain.Advisory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	advisories := make(map[string]domain.Advisory, len(ids))
+	for _, id := range ids {
+		if advisory, ok := r.advisories[id]; ok {
+			advisories[id] = advisory
+		}
+	}
+	return advisories, nil
+}
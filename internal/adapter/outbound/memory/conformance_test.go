@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/repotest"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlite"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Store {
+		return NewRepository()
+	})
+}
+
+func TestFiltersMatchSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Open db error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	reference, err := sqlite.NewRepository(db)
+	if err != nil {
+		t.Fatalf("Repository init error: %v", err)
+	}
+	memory := NewRepository()
+
+	ctx := context.Background()
+	for _, store := range []repotest.Store{reference, memory} {
+		if err := store.Save(ctx, repotest.Fixture(), nil); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+	}
+
+	columns := []string{"name", "version", "relation", "license", "score", "severity", "depth", "latest_version", "major_behind", "deprecated", "status", "unknown"}
+	operators := []domain.Operator{
+		domain.FilterEq, domain.FilterNe, domain.FilterLt, domain.FilterGte, domain.FilterIn,
+		domain.FilterPrefix, domain.FilterLike, domain.FilterIsNull, "between",
+	}
+	values := []string{"", "express", "EXPRESS", "MIT", "1", "1.5", "4.2", "7.5", "true", "false", "DIRECT,SELF", "%s%", "un_er%", "_", "6.%", "abc", "scored", "fetch_error"}
+
+	for _, column := range columns {
+		for _, operator := range operators {
+			for _, value := range values {
+				filters := []domain.Filter{{Column: column, Operator: operator, Value: value}}
+				for _, sort := range []domain.Sort{{}, {Column: "score", Desc: true}} {
+					query := domain.NodeQuery{Filters: filters, Sort: sort}
+					expected, expectedErr := reference.GetByName(ctx, "app", query)
+					got, gotErr := memory.GetByName(ctx, "app", query)
+					if (expectedErr == nil) != (gotErr == nil) || (gotErr != nil && gotErr.Error() != expectedErr.Error()) {
+						t.Errorf("%s %s %q: got error %v, expected %v", column, operator, value, gotErr, expectedErr)
+						continue
+					}
+					if gotErr != nil {
+						continue
+					}
+					if !slices.Equal(names(got), names(expected)) || got.Page.Total != expected.Page.Total {
+						t.Errorf("%s %s %q: got %v, expected %v", column, operator, value, names(got), names(expected))
+					}
+				}
+			}
+		}
+	}
+}
+
+func names(pkg *domain.Package) []string {
+	var result []string
+	for _, node := range pkg.Dependencies {
+		result = append(result, node.Name)
+	}
+	return result
+}
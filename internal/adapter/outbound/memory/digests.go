@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+func (r *Repository) LastDigestSent(ctx context.Context) (*time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return clone(r.digestSentAt), nil
+}
+
+func (r *Repository) SaveDigestSent(ctx context.Context, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sentAt = sentAt.UTC()
+	r.digestSentAt = &sentAt
+	return nil
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func matchesAll(filters []domain.Filter, node domain.DependencyNode) bool {
+	for _, f := range filters {
+		if !matches(f, node) {
+			return false
+		}
+	}
+	return true
+}
+
+func matches(f domain.Filter, node domain.DependencyNode) bool {
+	if f.IsGroup() {
+		if f.Logic != domain.LogicOr {
+			return matchesAll(f.Filters, node)
+		}
+		for _, child := range f.Filters {
+			if matches(child, node) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := field(f.Column, node)
+	switch f.Operator {
+	case domain.FilterIsNull:
+		return ok == (f.Value == "false")
+	case domain.FilterIn:
+		if !ok {
+			return false
+		}
+		for _, v := range strings.Split(f.Value, ",") {
+			if compare(value, strings.TrimSpace(v)) == 0 {
+				return true
+			}
+		}
+		return false
+	case domain.FilterPrefix:
+		return ok && like(escapeLike(f.Value)+"%", value.(string))
+	case domain.FilterLike:
+		return ok && like(f.Value, value.(string))
+	}
+	if !ok {
+		return false
+	}
+
+	c := compare(value, f.Value)
+	switch f.Operator {
+	case domain.FilterEq:
+		return c == 0
+	case domain.FilterNe:
+		return c != 0
+	case domain.FilterLt:
+		return c < 0
+	case domain.FilterLte:
+		return c <= 0
+	case domain.FilterGt:
+		return c > 0
+	case domain.FilterGte:
+		return c >= 0
+	}
+	return false
+}
+
+func field(column string, node domain.DependencyNode) (any, bool) {
+	switch column {
+	case "name":
+		return node.Name, true
+	case "version":
+		return node.Version, true
+	case "relation":
+		return node.Relation, true
+	case "license":
+		return node.License, node.License != ""
+	case "latest_version":
+		return node.LatestVersion, node.LatestVersion != ""
+	case "score":
+		if node.Score == nil {
+			return nil, false
+		}
+		return *node.Score, true
+	case "severity":
+		if node.Severity == nil {
+			return nil, false
+		}
+		return *node.Severity, true
+	case "depth":
+		return float64(node.Depth), true
+	case "major_behind":
+		return float64(node.Behind.Major), true
+	case "minor_behind":
+		return float64(node.Behind.Minor), true
+	case "patch_behind":
+		return float64(node.Behind.Patch), true
+	case "deprecated":
+		return node.Deprecated, true
+	case "status":
+		return string(node.Status), true
+	}
+	return nil, false
+}
+
+func compare(value any, filter string) int {
+	switch v := value.(type) {
+	case string:
+		return strings.Compare(v, filter)
+	case bool:
+		if v == (filter == "true") {
+			return 0
+		}
+		return 1
+	case float64:
+		parsed, _ := strconv.ParseFloat(filter, 64)
+		switch {
+		case v < parsed:
+			return -1
+		case v > parsed:
+			return 1
+		}
+	}
+	return 0
+}
+
+func like(pattern, value string) bool {
+	var b strings.Builder
+	b.WriteString(`(?is)^`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(`.*`)
+		case r == '_':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		return false
+	}
+	b.WriteString(`$`)
+	return regexp.MustCompile(b.String()).MatchString(value)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (r *Repository) ListEdges(ctx context.Context, name string) ([]domain.Edge, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.packages[name]
+	if !ok {
+		return nil, nil
+	}
+	var edges []domain.Edge
+	for _, e := range r.edges {
+		if e.packageId == record.id {
+			edges = append(edges, e.edge)
+		}
+	}
+	return edges, nil
+}
+
+func (r *Repository) FindDependents(ctx context.Context, dependency string, version string) ([]domain.Dependent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	packages := make(map[int64]domain.PackageRef, len(r.packages))
+	for _, record := range r.packages {
+		packages[record.id] = record.ref
+	}
+
+	var dependents []domain.Dependent
+	for _, n := range r.nodes {
+		if n.ref.Name != dependency || (version != "" && n.ref.Version != version) {
+			continue
+		}
+		dependents = append(dependents, domain.Dependent{
+			Package: packages[n.packageId],
+			Version: n.ref.Version,
+			Relation: n.relation,
+			Depth: n.depth,
+		})
+	}
+	slices.SortStableFunc(dependents, func(a, b domain.Dependent) int {
+		if c := cmp.Compare(a.Package.Name, b.Package.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return dependents, nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type snapshotRecord struct {
+	snapshot domain.Snapshot
+	nodes []domain.DependencyNode
+}
+
+func (r *Repository) saveSnapshot(pkg *domain.Package, changes []domain.Change) {
+	nodes := make([]domain.DependencyNode, len(pkg.Dependencies))
+	for i, node := range pkg.Dependencies {
+		nodes[i] = domain.DependencyNode{Name: node.Name, Version: node.Version, Score: clone(node.Score)}
+	}
+	r.snapshots = append(r.snapshots, snapshotRecord{
+		snapshot: domain.Snapshot{
+			ID: r.next("snapshots"),
+			PackageRef: pkg.PackageRef,
+			TakenAt: pkg.LastUpdatedAt,
+			Health: clone(pkg.Health),
+			Changes: slices.Clone(changes),
+		},
+		nodes: nodes,
+	})
+}
+
+func (r *Repository) ListSnapshots(ctx context.Context, name string, limit int) ([]domain.Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var snapshots []domain.Snapshot
+	for _, record := range r.snapshots {
+		if name != "" && record.snapshot.PackageRef.Name != name {
+			continue
+		}
+		snapshot := record.snapshot
+		snapshot.Health = clone(snapshot.Health)
+		snapshot.Changes = slices.Clone(snapshot.Changes)
+		if len(snapshot.Changes) == 0 {
+			snapshot.Changes = nil
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	slices.SortFunc(snapshots, func(a, b domain.Snapshot) int {
+		if c := b.TakenAt.Compare(a.TakenAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+	return snapshots, nil
+}
+
+func (r *Repository) PruneSnapshots(ctx context.Context, name string, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var owned []snapshotRecord
+	for _, record := range r.snapshots {
+		if record.snapshot.PackageRef.Name == name {
+			owned = append(owned, record)
+		}
+	}
+	if len(owned) <= keep {
+		return nil
+	}
+	slices.SortFunc(owned, func(a, b snapshotRecord) int {
+		if c := b.snapshot.TakenAt.Compare(a.snapshot.TakenAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.snapshot.ID, a.snapshot.ID)
+	})
+	pruned := make(map[int64]bool)
+	for _, record := range owned[keep:] {
+		pruned[record.snapshot.ID] = true
+	}
+	r.snapshots = slices.DeleteFunc(r.snapshots, func(record snapshotRecord) bool {
+		return pruned[record.snapshot.ID]
+	})
+	return nil
+}
+
+func (r *Repository) ListScoreHistory(ctx context.Context, name string, dependency string) ([]domain.ScorePoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := slices.Clone(r.snapshots)
+	slices.SortStableFunc(records, func(a, b snapshotRecord) int {
+		if c := a.snapshot.TakenAt.Compare(b.snapshot.TakenAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.snapshot.ID, b.snapshot.ID)
+	})
+
+	var points []domain.ScorePoint
+	for _, record := range records {
+		if record.snapshot.PackageRef.Name != name {
+			continue
+		}
+		var versions []domain.ScorePoint
+		for _, node := range record.nodes {
+			if node.Name == dependency {
+				versions = append(versions, domain.ScorePoint{TakenAt: record.snapshot.TakenAt, Version: node.Version, Score: clone(node.Score)})
+			}
+		}
+		slices.SortStableFunc(versions, func(a, b domain.ScorePoint) int { return cmp.Compare(a.Version, b.Version) })
+		points = append(points, versions...)
+	}
+	return points, nil
+}
@@ -0,0 +1,334 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlstore"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type packageRecord struct {
+	id int64
+	ref domain.PackageRef
+	lastUpdatedAt time.Time
+	health *float64
+}
+
+type nodeRecord struct {
+	id int64
+	packageId int64
+	ref domain.PackageRef
+	relation string
+	depth int
+	fanIn int
+	status domain.EnrichmentStatus
+	statusMessage string
+}
+
+type edgeRecord struct {
+	packageId int64
+	edge domain.Edge
+}
+
+type Repository struct {
+	mu sync.RWMutex
+	sequences map[string]int64
+	packages map[string]*packageRecord
+	versions map[domain.PackageRef]domain.DependencyNode
+	projects map[string]domain.Project
+	advisories map[string]domain.Advisory
+	nodes []nodeRecord
+	edges []edgeRecord
+	snapshots []snapshotRecord
+	alerts []domain.Alert
+	webhooks []domain.Webhook
+	deliveries []domain.WebhookDelivery
+	digestSentAt *time.Time
+}
+
+func NewRepository() *Repository {
+	return &Repository{
+		sequences: make(map[string]int64),
+		packages: make(map[string]*packageRecord),
+		versions: make(map[domain.PackageRef]domain.DependencyNode),
+		projects: make(map[string]domain.Project),
+		advisories: make(map[string]domain.Advisory),
+	}
+}
+
+func (r *Repository) next(table string) int64 {
+	r.sequences[table]++
+	return r.sequences[table]
+}
+
+func (r *Repository) Save(ctx context.Context, pkg *domain.Package, changes []domain.Change) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.packages[pkg.PackageRef.Name]
+	if !ok {
+		record = &packageRecord{id: r.next("packages")}
+		r.packages[pkg.PackageRef.Name] = record
+	}
+	record.ref = pkg.PackageRef
+	record.lastUpdatedAt = pkg.LastUpdatedAt
+	record.health = clone(pkg.Health)
+	pkg.ID = record.id
+
+	r.nodes = slices.DeleteFunc(r.nodes, func(n nodeRecord) bool { return n.packageId == record.id })
+	r.edges = slices.DeleteFunc(r.edges, func(e edgeRecord) bool { return e.packageId == record.id })
+
+	saved := make(map[domain.PackageRef]bool, len(pkg.Dependencies))
+	for _, node := range pkg.Dependencies {
+		if node.Project != nil {
+			r.projects[node.Project.Key] = storedProject(*node.Project, r.projects[node.Project.Key])
+		}
+		ref := domain.PackageRef{Name: node.Name, Version: node.Version}
+		r.versions[ref] = storedVersion(node)
+		saved[ref] = true
+		r.nodes = append(r.nodes, nodeRecord{
+			id: r.next("dependency_nodes"),
+			packageId: record.id,
+			ref: ref,
+			relation: node.Relation,
+			depth: node.Depth,
+			fanIn: node.FanIn,
+			status: node.Status,
+			statusMessage: node.StatusMessage,
+		})
+	}
+	for _, advisory := range pkg.Advisories {
+		r.advisories[advisory.ID] = advisory
+	}
+	for _, edge := range pkg.Edges {
+		if saved[edge.From] && saved[edge.To] {
+			r.edges = append(r.edges, edgeRecord{packageId: record.id, edge: edge})
+		}
+	}
+
+	r.saveSnapshot(pkg, changes)
+	return nil
+}
+
+func (r *Repository) GetCurrent(ctx context.Context, query domain.NodeQuery) (*domain.Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var current *packageRecord
+	for _, record := range r.packages {
+		if current == nil || record.lastUpdatedAt.After(current.lastUpdatedAt) ||
+			(record.lastUpdatedAt.Equal(current.lastUpdatedAt) && record.id > current.id) {
+			current = record
+		}
+	}
+	if current == nil {
+		return nil, domain.ErrNotFound
+	}
+	return r.packageWithDeps(current, query)
+}
+
+func (r *Repository) GetByName(ctx context.Context, name string, query domain.NodeQuery) (*domain.Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.packages[name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return r.packageWithDeps(record, query)
+}
+
+func (r *Repository) List(ctx context.Context) ([]*domain.Package, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]*packageRecord, 0, len(r.packages))
+	for _, record := range r.packages {
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b *packageRecord) int { return cmp.Compare(a.ref.Name, b.ref.Name) })
+
+	packages := make([]*domain.Package, 0, len(records))
+	for _, record := range records {
+		pkg, err := r.packageWithDeps(record, domain.NodeQuery{})
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+func (r *Repository) DeleteByName(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.packages[name]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.packages, name)
+	r.nodes = slices.DeleteFunc(r.nodes, func(n nodeRecord) bool { return n.packageId == record.id })
+	r.edges = slices.DeleteFunc(r.edges, func(e edgeRecord) bool { return e.packageId == record.id })
+	return nil
+}
+
+func (r *Repository) packageWithDeps(record *packageRecord, query domain.NodeQuery) (*domain.Package, error) {
+	if _, _, err := sqlstore.BuildFilters(query.Filters, sqlstore.SQLite); err != nil {
+		return nil, fmt.Errorf("Invalid filter error: %w", err)
+	}
+	order, err := sqlstore.BuildOrder(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+	var after func(nodeRecord, domain.DependencyNode) bool
+	if query.Cursor != "" {
+		key, id, err := order.Decode(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = func(n nodeRecord, node domain.DependencyNode) bool {
+			c := compareKeys(sortKey(query.Sort.Column, n, node), key)
+			if query.Sort.Desc {
+				c = -c
+			}
+			return c > 0 || (c == 0 && n.id > id)
+		}
+	}
+
+	pkg := &domain.Package{
+		ID: record.id,
+		PackageRef: record.ref,
+		LastUpdatedAt: record.lastUpdatedAt,
+		Health: clone(record.health),
+		Page: &domain.Page{Limit: query.Limit},
+	}
+
+	type row struct {
+		record nodeRecord
+		node domain.DependencyNode
+	}
+	var rows []row
+	for _, n := range r.nodes {
+		if n.packageId != record.id {
+			continue
+		}
+		node := r.graphNode(n)
+		if !matchesAll(query.Filters, node) {
+			continue
+		}
+		pkg.Page.Total++
+		if after == nil || after(n, node) {
+			rows = append(rows, row{n, node})
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b row) int {
+		c := compareKeys(sortKey(query.Sort.Column, a.record, a.node), sortKey(query.Sort.Column, b.record, b.node))
+		if query.Sort.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return cmp.Compare(a.record.id, b.record.id)
+	})
+
+	for i, row := range rows {
+		if query.Limit > 0 && i == query.Limit {
+			last := rows[i-1]
+			pkg.Page.NextCursor = order.Cursor(sortKey(query.Sort.Column, last.record, last.node), last.record.id)
+			break
+		}
+		pkg.Dependencies = append(pkg.Dependencies, row.node)
+	}
+	return pkg, nil
+}
+
+func (r *Repository) graphNode(n nodeRecord) domain.DependencyNode {
+	node := copyVersion(r.versions[n.ref])
+	node.Relation = n.relation
+	node.Depth = n.depth
+	node.FanIn = n.fanIn
+	node.Status = n.status
+	node.StatusMessage = n.statusMessage
+	if project, ok := r.projects[node.ProjectKey]; ok && node.ProjectKey != "" {
+		project.Scorecard.Checks = nil
+		node.Project = &project
+		if project.HasScorecard() {
+			node.Score = &project.Scorecard.Score
+		}
+	}
+	return node
+}
+
+func sortKey(column string, n nodeRecord, node domain.DependencyNode) any {
+	switch column {
+	case "name":
+		return node.Name
+	case "score":
+		if node.Score == nil {
+			return -1.0
+		}
+		return *node.Score
+	case "depth":
+		return float64(node.Depth)
+	default:
+		return float64(n.id)
+	}
+}
+
+func compareKeys(a, b any) int {
+	if text, ok := a.(string); ok {
+		return cmp.Compare(text, b.(string))
+	}
+	return cmp.Compare(a.(float64), b.(float64))
+}
+
+func storedVersion(node domain.DependencyNode) domain.DependencyNode {
+	return copyVersion(domain.DependencyNode{
+		Name: node.Name,
+		Version: node.Version,
+		Advisories: node.Advisories,
+		Severity: node.Severity,
+		License: node.License,
+		ProjectKey: node.ProjectKey,
+		LatestVersion: node.LatestVersion,
+		Behind: node.Behind,
+		Deprecated: node.Deprecated,
+		DeprecationReason: node.DeprecationReason,
+		PublishedAt: node.PublishedAt,
+		LatestReleaseAt: node.LatestReleaseAt,
+	})
+}
+
+func copyVersion(node domain.DependencyNode) domain.DependencyNode {
+	node.Advisories = slices.Clone(node.Advisories)
+	if len(node.Advisories) == 0 {
+		node.Advisories = nil
+	}
+	node.Severity = clone(node.Severity)
+	node.PublishedAt = clone(node.PublishedAt)
+	node.LatestReleaseAt = clone(node.LatestReleaseAt)
+	return node
+}
+
+func storedProject(project domain.Project, existing domain.Project) domain.Project {
+	if project.Scorecard.Checks == nil {
+		project.Scorecard.Checks = existing.Scorecard.Checks
+	}
+	project.Scorecard.Checks = slices.Clone(project.Scorecard.Checks)
+	return project
+}
+
+func clone[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
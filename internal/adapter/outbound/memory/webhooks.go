@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (r *Repository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook.ID = r.next("webhooks")
+	stored := *webhook
+	stored.Events = slices.Clone(webhook.Events)
+	r.webhooks = append(r.webhooks, stored)
+	return nil
+}
+
+func (r *Repository) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var webhooks []domain.Webhook
+	for _, webhook := range r.webhooks {
+		webhook.Events = slices.Clone(webhook.Events)
+		if len(webhook.Events) == 0 {
+			webhook.Events = nil
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.hasWebhook(id) {
+		return domain.ErrWebhookNotFound
+	}
+	r.webhooks = slices.DeleteFunc(r.webhooks, func(w domain.Webhook) bool { return w.ID == id })
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(d domain.WebhookDelivery) bool { return d.WebhookID == id })
+	return nil
+}
+
+func (r *Repository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.hasWebhook(delivery.WebhookID) {
+		return fmt.Errorf("Insert delivery error: webhook %d does not exist", delivery.WebhookID)
+	}
+	delivery.ID = r.next("webhook_deliveries")
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func (r *Repository) ListDeliveries(ctx context.Context, webhookID int64) ([]domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.hasWebhook(webhookID) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	slices.SortFunc(deliveries, func(a, b domain.WebhookDelivery) int {
+		if c := b.DeliveredAt.Compare(a.DeliveredAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return deliveries, nil
+}
+
+func (r *Repository) hasWebhook(id int64) bool {
+	return slices.ContainsFunc(r.webhooks, func(w domain.Webhook) bool { return w.ID == id })
+}
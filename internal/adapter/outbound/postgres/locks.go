@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+)
+
+const (
+	packageLockClass = 0x64657073
+	migrationLockClass = 0x6d696772
+	lockQuery = `SELECT true FROM pg_advisory_lock($1, hashtext($2))`
+	tryLockQuery = `SELECT pg_try_advisory_lock($1, hashtext($2))`
+)
+
+func (r *Repository) LockPackage(ctx context.Context, name string) (func(), error) {
+	unlock, _, err := advisoryLock(ctx, r.db, packageLockClass, name, lockQuery)
+	return unlock, err
+}
+
+func (r *Repository) TryLockPackage(ctx context.Context, name string) (func(), bool, error) {
+	return advisoryLock(ctx, r.db, packageLockClass, name, tryLockQuery)
+}
+
+func advisoryLock(ctx context.Context, db *sql.DB, class int, name string, query string) (func(), bool, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("Lock connection error: %w", err)
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, query, class, name).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("Advisory lock error: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+	return func() { advisoryUnlock(conn, class, name) }, true, nil
+}
+
+func advisoryUnlock(conn *sql.Conn, class int, name string) {
+	defer conn.Close()
+	if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1, hashtext($2))`, class, name); err != nil {
+		log.Printf("Advisory unlock error: %v", err)
+		conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlstore"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+func NewMigrator(db *sql.DB) (*sqlstore.Migrator, error) {
+	return sqlstore.NewMigrator(db, migrationFiles, sqlstore.Postgres, nil, func(ctx context.Context) (func(), error) {
+		unlock, _, err := advisoryLock(ctx, db, migrationLockClass, "schema_migrations", lockQuery)
+		return unlock, err
+	})
+}
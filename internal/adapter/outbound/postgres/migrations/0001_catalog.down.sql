@@ -0,0 +1,6 @@
+DROP VIEW IF EXISTS graph_nodes;
+DROP TABLE IF EXISTS dependency_edges;
+DROP TABLE IF EXISTS dependency_nodes;
+DROP TABLE IF EXISTS package_versions;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS packages;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS snapshot_changes;
+DROP TABLE IF EXISTS snapshot_nodes;
+DROP TABLE IF EXISTS snapshots;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS snapshots (
+	id				BIGSERIAL PRIMARY KEY,
+	package_name	TEXT NOT NULL,
+	version			TEXT NOT NULL,
+	taken_at		TIMESTAMPTZ NOT NULL,
+	health			DOUBLE PRECISION
+);
+
+CREATE INDEX IF NOT EXISTS snapshots_package_name ON snapshots (package_name, taken_at);
+
+CREATE TABLE IF NOT EXISTS snapshot_nodes (
+	id			BIGSERIAL PRIMARY KEY,
+	snapshot_id	BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+	name		TEXT NOT NULL,
+	version		TEXT COLLATE "C" NOT NULL,
+	relation	TEXT NOT NULL,
+	score		DOUBLE PRECISION,
+	advisories	TEXT NOT NULL DEFAULT '',
+	severity	DOUBLE PRECISION,
+	license		TEXT,
+	depth		INTEGER NOT NULL DEFAULT 1,
+	fan_in		INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS snapshot_changes (
+	id			BIGSERIAL PRIMARY KEY,
+	snapshot_id	BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+	kind		TEXT NOT NULL,
+	dependency	TEXT NOT NULL,
+	from_value	TEXT NOT NULL DEFAULT '',
+	to_value	TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS advisories;
+
+ALTER TABLE projects DROP COLUMN scorecard_checks;
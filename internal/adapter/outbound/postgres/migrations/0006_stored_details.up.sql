@@ -0,0 +1,8 @@
+ALTER TABLE projects ADD COLUMN scorecard_checks TEXT;
+
+CREATE TABLE IF NOT EXISTS advisories (
+	advisory_id	TEXT PRIMARY KEY,
+	title		TEXT NOT NULL DEFAULT '',
+	cvss3_score	DOUBLE PRECISION,
+	fetched_at	TIMESTAMPTZ NOT NULL
+);
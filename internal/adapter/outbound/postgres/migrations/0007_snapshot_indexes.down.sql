@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS snapshot_changes_snapshot_id;
+DROP INDEX IF EXISTS snapshot_nodes_name;
+DROP INDEX IF EXISTS snapshot_nodes_snapshot_id;
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS digests (
+	id		INTEGER PRIMARY KEY CHECK (id = 1),
+	sent_at	TIMESTAMPTZ NOT NULL
+);
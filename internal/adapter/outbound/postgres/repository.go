@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlstore"
+)
+
+type Repository struct {
+	*sqlstore.Store
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) (*Repository, error) {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return nil, fmt.Errorf("Applying migrations: %w", err)
+	}
+	return &Repository{Store: sqlstore.NewStore(db, sqlstore.Postgres), db: db}, nil
+}
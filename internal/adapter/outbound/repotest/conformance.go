@@ -0,0 +1,742 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+type Store interface {
+	outbound.Repository
+	outbound.AlertRepository
+	outbound.DigestRepository
+	outbound.WebhookRepository
+}
+
+var baseTime = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func ref(name, version string) domain.PackageRef {
+	return domain.PackageRef{Name: name, Version: version}
+}
+
+func project(key string, score float64) *domain.Project {
+	return &domain.Project{
+		Key: key,
+		Description: "Project " + key,
+		Stars: 120,
+		OSSFuzz: true,
+		Scorecard: domain.Scorecard{Score: score, Date: baseTime.Add(-48 * time.Hour)},
+		FetchedAt: baseTime,
+	}
+}
+
+func Fixture() *domain.Package {
+	published := baseTime.Add(-30 * 24 * time.Hour)
+	return &domain.Package{
+		PackageRef: ref("app", "1.0.0"),
+		LastUpdatedAt: baseTime,
+		Health: float(6.5),
+		Dependencies: []domain.DependencyNode{
+			{Name: "app", Version: "1.0.0", Relation: "SELF", License: "MIT", Status: domain.EnrichmentNoProject},
+			{Name: "express", Version: "5.2.1", Relation: "DIRECT", Depth: 1, FanIn: 1, License: "MIT",
+				ProjectKey: "github.com/expressjs/express", Project: project("github.com/expressjs/express", 7.5), Score: float(7.5),
+				Status: domain.EnrichmentScored},
+			{Name: "qs", Version: "6.14.0", Relation: "INDIRECT", Depth: 2, FanIn: 1, License: "BSD-3-Clause",
+				Advisories: []string{"GHSA-1"}, Severity: float(7.5), LatestVersion: "6.15.0", Behind: domain.VersionLag{Minor: 1},
+				PublishedAt: &published, ProjectKey: "github.com/ljharb/qs", Project: project("github.com/ljharb/qs", 4.2), Score: float(4.2),
+				Status: domain.EnrichmentScored},
+			{Name: "Debug", Version: "2.6.9", Relation: "INDIRECT", Depth: 2, FanIn: 1,
+				Deprecated: true, DeprecationReason: "Use debug instead", LatestVersion: "4.4.0", Behind: domain.VersionLag{Major: 2},
+				Status: domain.EnrichmentFetchError, StatusMessage: "Error from deps.dev: 503"},
+			{Name: "under_score", Version: "1.0.0", Relation: "DIRECT", Depth: 1, FanIn: 1, License: "MIT",
+				ProjectKey: "github.com/x/under_score", Project: project("github.com/x/under_score", 5.5), Score: float(5.5),
+				Status: domain.EnrichmentScored},
+		},
+		Edges: []domain.Edge{
+			{From: ref("app", "1.0.0"), To: ref("express", "5.2.1")},
+			{From: ref("express", "5.2.1"), To: ref("qs", "6.14.0")},
+			{From: ref("express", "5.2.1"), To: ref("Debug", "2.6.9")},
+			{From: ref("app", "1.0.0"), To: ref("under_score", "1.0.0")},
+		},
+	}
+}
+
+func names(pkg *domain.Package) []string {
+	var result []string
+	for _, node := range pkg.Dependencies {
+		result = append(result, node.Name)
+	}
+	return result
+}
+
+func save(t *testing.T, store Store, pkg *domain.Package, changes []domain.Change) {
+	t.Helper()
+	if err := store.Save(context.Background(), pkg, changes); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+}
+
+func Run(t *testing.T, open func(t *testing.T) Store) {
+	t.Run("round trip", func(t *testing.T) { testRoundTrip(t, open(t)) })
+	t.Run("filters", func(t *testing.T) { testFilters(t, open(t)) })
+	t.Run("sort and pagination", func(t *testing.T) { testPagination(t, open(t)) })
+	t.Run("paging through ties", func(t *testing.T) { testPagingTies(t, open(t)) })
+	t.Run("packages", func(t *testing.T) { testPackages(t, open(t)) })
+	t.Run("dependents", func(t *testing.T) { testDependents(t, open(t)) })
+	t.Run("history", func(t *testing.T) { testHistory(t, open(t)) })
+	t.Run("snapshot pruning", func(t *testing.T) { testPruneSnapshots(t, open(t)) })
+	t.Run("alerts", func(t *testing.T) { testAlerts(t, open(t)) })
+	t.Run("digests", func(t *testing.T) { testDigests(t, open(t)) })
+	t.Run("webhooks", func(t *testing.T) { testWebhooks(t, open(t)) })
+}
+
+func testRoundTrip(t *testing.T, store Store) {
+	ctx := context.Background()
+	save(t, store, Fixture(), nil)
+
+	pkg, err := store.GetByName(ctx, "app", domain.NodeQuery{})
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if pkg.PackageRef != ref("app", "1.0.0") || !pkg.LastUpdatedAt.Equal(baseTime) || pkg.Health == nil || *pkg.Health != 6.5 {
+		t.Errorf("Got package %+v, expected app 1.0.0 updated at %v with health 6.5", pkg, baseTime)
+	}
+	if pkg.Page.Total != 5 || !slices.Equal(names(pkg), []string{"app", "express", "qs", "Debug", "under_score"}) {
+		t.Fatalf("Got nodes %v (total %d), expected fixture order", names(pkg), pkg.Page.Total)
+	}
+
+	qs := pkg.Dependencies[2]
+	if !slices.Equal(qs.Advisories, []string{"GHSA-1"}) || qs.Severity == nil || *qs.Severity != 7.5 || qs.License != "BSD-3-Clause" ||
+		qs.LatestVersion != "6.15.0" || qs.Behind != (domain.VersionLag{Minor: 1}) || qs.Depth != 2 || qs.FanIn != 1 {
+		t.Errorf("Got qs %+v, expected fixture values", qs)
+	}
+	if qs.PublishedAt == nil || !qs.PublishedAt.Equal(baseTime.Add(-30*24*time.Hour)) {
+		t.Errorf("Got published at %v, expected %v", qs.PublishedAt, baseTime.Add(-30*24*time.Hour))
+	}
+	if qs.Score == nil || *qs.Score != 4.2 || qs.Project == nil || qs.Project.Stars != 120 || !qs.Project.OSSFuzz ||
+		qs.Project.Scorecard.Score != 4.2 || !qs.Project.FetchedAt.Equal(baseTime) {
+		t.Errorf("Got qs project %+v, expected fixture project", qs.Project)
+	}
+	debug := pkg.Dependencies[3]
+	if !debug.Deprecated || debug.DeprecationReason != "Use debug instead" || debug.Score != nil || debug.Project != nil || debug.License != "" {
+		t.Errorf("Got Debug %+v, expected deprecated node without project", debug)
+	}
+	if debug.Status != domain.EnrichmentFetchError || debug.StatusMessage != "Error from deps.dev: 503" || qs.Status != domain.EnrichmentScored {
+		t.Errorf("Got statuses %s (%q) and %s, expected fetch error and scored", debug.Status, debug.StatusMessage, qs.Status)
+	}
+
+	edges, err := store.ListEdges(ctx, "app")
+	if err != nil {
+		t.Fatalf("List edges error: %v", err)
+	}
+	if !slices.Equal(edges, Fixture().Edges) {
+		t.Errorf("Got edges %v, expected %v", edges, Fixture().Edges)
+	}
+
+	projects, err := store.GetProjects(ctx, []string{"github.com/ljharb/qs", "github.com/unknown/project"})
+	if err != nil {
+		t.Fatalf("Get projects error: %v", err)
+	}
+	if len(projects) != 1 || projects["github.com/ljharb/qs"].Scorecard.Score != 4.2 {
+		t.Errorf("Got projects %v, expected qs only", projects)
+	}
+
+	if _, err := store.GetByName(ctx, "missing", domain.NodeQuery{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Got error %v, expected ErrNotFound", err)
+	}
+
+	unscored := &domain.Project{Key: "github.com/x/lib", Stars: 3, FetchedAt: baseTime}
+	save(t, store, &domain.Package{
+		PackageRef: ref("lib", "1.0.0"),
+		LastUpdatedAt: baseTime,
+		Dependencies: []domain.DependencyNode{
+			{Name: "lib", Version: "1.0.0", Relation: "SELF", ProjectKey: unscored.Key, Project: unscored, Status: domain.EnrichmentNoScorecard},
+		},
+	}, nil)
+	lib, err := store.GetByName(ctx, "lib", domain.NodeQuery{})
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if node := lib.Dependencies[0]; node.Score != nil || node.Project == nil || node.Project.Stars != 3 || node.Status != domain.EnrichmentNoScorecard {
+		t.Errorf("Got %+v, expected project without score", node)
+	}
+}
+
+func testFilters(t *testing.T, store Store) {
+	save(t, store, Fixture(), nil)
+
+	tests := []struct {
+		name string
+		filters []domain.Filter
+		expected []string
+		error bool
+	}{
+		{
+			name: "eq",
+			filters: []domain.Filter{{Column: "name", Operator: domain.FilterEq, Value: "express"}},
+			expected: []string{"express"},
+		},
+		{
+			name: "ne skips nulls",
+			filters: []domain.Filter{{Column: "license", Operator: domain.FilterNe, Value: "MIT"}},
+			expected: []string{"qs"},
+		},
+		{
+			name: "gte skips unscored",
+			filters: []domain.Filter{{Column: "score", Operator: domain.FilterGte, Value: "5"}},
+			expected: []string{"express", "under_score"},
+		},
+		{
+			name: "fractional value on integer column",
+			filters: []domain.Filter{{Column: "depth", Operator: domain.FilterLte, Value: "1.5"}},
+			expected: []string{"app", "express", "under_score"},
+		},
+		{
+			name: "in",
+			filters: []domain.Filter{{Column: "relation", Operator: domain.FilterIn, Value: "DIRECT, SELF"}},
+			expected: []string{"app", "express", "under_score"},
+		},
+		{
+			name: "prefix escapes wildcards",
+			filters: []domain.Filter{{Column: "name", Operator: domain.FilterPrefix, Value: "under_"}},
+			expected: []string{"under_score"},
+		},
+		{
+			name: "like ignores case",
+			filters: []domain.Filter{{Column: "name", Operator: domain.FilterLike, Value: "debug"}},
+			expected: []string{"Debug"},
+		},
+		{
+			name: "isnull",
+			filters: []domain.Filter{{Column: "score", Operator: domain.FilterIsNull}},
+			expected: []string{"app", "Debug"},
+		},
+		{
+			name: "boolean",
+			filters: []domain.Filter{{Column: "deprecated", Operator: domain.FilterEq, Value: "true"}},
+			expected: []string{"Debug"},
+		},
+		{
+			name: "not deprecated",
+			filters: []domain.Filter{{Column: "deprecated", Operator: domain.FilterEq, Value: "false"}},
+			expected: []string{"app", "express", "qs", "under_score"},
+		},
+		{
+			name: "invalid boolean",
+			filters: []domain.Filter{{Column: "deprecated", Operator: domain.FilterEq, Value: "yes"}},
+			error: true,
+		},
+		{
+			name: "outdated",
+			filters: []domain.Filter{{Logic: domain.LogicOr, Filters: []domain.Filter{
+				{Column: "major_behind", Operator: domain.FilterGt, Value: "0"},
+				{Column: "minor_behind", Operator: domain.FilterGt, Value: "0"},
+				{Column: "patch_behind", Operator: domain.FilterGt, Value: "0"},
+			}}},
+			expected: []string{"qs", "Debug"},
+		},
+		{
+			name: "up to date",
+			filters: []domain.Filter{
+				{Column: "major_behind", Operator: domain.FilterEq, Value: "0"},
+				{Column: "minor_behind", Operator: domain.FilterEq, Value: "0"},
+				{Column: "patch_behind", Operator: domain.FilterEq, Value: "0"},
+			},
+			expected: []string{"app", "express", "under_score"},
+		},
+		{
+			name: "outdated and deprecated",
+			filters: []domain.Filter{
+				{Column: "deprecated", Operator: domain.FilterEq, Value: "true"},
+				{Column: "major_behind", Operator: domain.FilterGt, Value: "0"},
+			},
+			expected: []string{"Debug"},
+		},
+		{
+			name: "status",
+			filters: []domain.Filter{{Column: "status", Operator: domain.FilterNe, Value: "scored"}},
+			expected: []string{"app", "Debug"},
+		},
+		{
+			name: "or group",
+			filters: []domain.Filter{{Logic: domain.LogicOr, Filters: []domain.Filter{
+				{Column: "depth", Operator: domain.FilterEq, Value: "0"},
+				{Column: "severity", Operator: domain.FilterGt, Value: "7"},
+				{Column: "major_behind", Operator: domain.FilterGt, Value: "0"},
+			}}},
+			expected: []string{"app", "qs", "Debug"},
+		},
+		{
+			name: "unknown column",
+			filters: []domain.Filter{{Column: "secret", Operator: domain.FilterEq, Value: "x"}},
+			error: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, err := store.GetByName(context.Background(), "app", domain.NodeQuery{Filters: tt.filters})
+			if tt.error {
+				if err == nil {
+					t.Fatalf("Got no error, expected invalid filter")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get error: %v", err)
+			}
+			if !slices.Equal(names(pkg), tt.expected) || pkg.Page.Total != len(tt.expected) {
+				t.Errorf("Got %v (total %d), expected %v", names(pkg), pkg.Page.Total, tt.expected)
+			}
+		})
+	}
+}
+
+func testPagination(t *testing.T, store Store) {
+	save(t, store, Fixture(), nil)
+
+	tests := []struct {
+		name string
+		sort domain.Sort
+		expected [][]string
+	}{
+		{"score descending", domain.Sort{Column: "score", Desc: true}, [][]string{{"express", "under_score"}, {"qs", "app"}, {"Debug"}}},
+		{"name ascending", domain.Sort{Column: "name"}, [][]string{{"Debug", "app"}, {"express", "qs"}, {"under_score"}}},
+		{"depth ascending", domain.Sort{Column: "depth"}, [][]string{{"app", "express"}, {"under_score", "qs"}, {"Debug"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := domain.NodeQuery{Sort: tt.sort, Limit: 2}
+			var pages [][]string
+			for range len(tt.expected) + 1 {
+				pkg, err := store.GetByName(context.Background(), "app", query)
+				if err != nil {
+					t.Fatalf("Get error: %v", err)
+				}
+				if pkg.Page.Total != 5 {
+					t.Errorf("Got total %d, expected 5", pkg.Page.Total)
+				}
+				pages = append(pages, names(pkg))
+				if pkg.Page.NextCursor == "" {
+					break
+				}
+				query.Cursor = pkg.Page.NextCursor
+			}
+			if !slices.EqualFunc(pages, tt.expected, slices.Equal) {
+				t.Errorf("Got pages %v, expected %v", pages, tt.expected)
+			}
+		})
+	}
+
+	if _, err := store.GetByName(context.Background(), "app", domain.NodeQuery{Limit: 2, Cursor: "garbage"}); err == nil {
+		t.Errorf("Got no error, expected invalid cursor")
+	}
+	if _, err := store.GetByName(context.Background(), "app", domain.NodeQuery{Sort: domain.Sort{Column: "secret"}}); err == nil {
+		t.Errorf("Got no error, expected unknown sort")
+	}
+}
+
+func testPagingTies(t *testing.T, store Store) {
+	pkg := &domain.Package{PackageRef: ref("big", "1.0.0"), LastUpdatedAt: baseTime}
+	scores := []float64{3, 7.5, 7.5, 0}
+	for i := range 30 {
+		node := domain.DependencyNode{
+			Name: fmt.Sprintf("dep-%d", i%12),
+			Version: fmt.Sprintf("1.%d.0", i/12),
+			Relation: "INDIRECT",
+			Depth: 1 + i%3,
+		}
+		if score := scores[i%len(scores)]; score > 0 {
+			node.ProjectKey = fmt.Sprintf("github.com/example/dep-%d", i)
+			node.Project = project(node.ProjectKey, score)
+		}
+		pkg.Dependencies = append(pkg.Dependencies, node)
+	}
+	save(t, store, pkg, nil)
+
+	var sorts []domain.Sort
+	for _, column := range []string{"", "name", "score", "depth"} {
+		sorts = append(sorts, domain.Sort{Column: column}, domain.Sort{Column: column, Desc: true})
+	}
+	for _, sort := range sorts {
+		full, err := store.GetByName(context.Background(), "big", domain.NodeQuery{Sort: sort})
+		if err != nil {
+			t.Fatalf("Get error: %v", err)
+		}
+		expected := refs(full)
+		if len(expected) != 30 {
+			t.Fatalf("Got %d nodes, expected 30", len(expected))
+		}
+		for _, limit := range []int{1, 4, 7, 30, 31} {
+			query := domain.NodeQuery{Sort: sort, Limit: limit}
+			var paged []domain.PackageRef
+			for range len(expected) + 1 {
+				page, err := store.GetByName(context.Background(), "big", query)
+				if err != nil {
+					t.Fatalf("Get error: %v", err)
+				}
+				if len(page.Dependencies) > limit {
+					t.Fatalf("Got %d nodes, expected at most %d", len(page.Dependencies), limit)
+				}
+				paged = append(paged, refs(page)...)
+				if page.Page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.Page.NextCursor
+			}
+			if !slices.Equal(paged, expected) {
+				t.Errorf("Sort %+v limit %d: got %v, expected %v", sort, limit, paged, expected)
+			}
+		}
+	}
+
+	nameCursor := func() string {
+		page, err := store.GetByName(context.Background(), "big", domain.NodeQuery{Sort: domain.Sort{Column: "name"}, Limit: 1})
+		if err != nil {
+			t.Fatalf("Get error: %v", err)
+		}
+		return page.Page.NextCursor
+	}()
+	if _, err := store.GetByName(context.Background(), "big", domain.NodeQuery{Sort: domain.Sort{Column: "score"}, Limit: 1, Cursor: nameCursor}); domain.KindOf(err) != domain.KindInvalidInput {
+		t.Errorf("Got %v, expected invalid cursor for another sort", err)
+	}
+}
+
+func refs(pkg *domain.Package) []domain.PackageRef {
+	var result []domain.PackageRef
+	for _, node := range pkg.Dependencies {
+		result = append(result, ref(node.Name, node.Version))
+	}
+	return result
+}
+
+func testPackages(t *testing.T, store Store) {
+	ctx := context.Background()
+	save(t, store, Fixture(), nil)
+	other := &domain.Package{
+		PackageRef: ref("other", "2.0.0"),
+		LastUpdatedAt: baseTime.Add(time.Hour),
+		Dependencies: []domain.DependencyNode{
+			{Name: "other", Version: "2.0.0", Relation: "SELF"},
+			{Name: "qs", Version: "6.14.0", Relation: "DIRECT", Depth: 1, FanIn: 1},
+		},
+	}
+	save(t, store, other, nil)
+
+	current, err := store.GetCurrent(ctx, domain.NodeQuery{})
+	if err != nil {
+		t.Fatalf("Get current error: %v", err)
+	}
+	if current.PackageRef.Name != "other" {
+		t.Errorf("Got current %s, expected most recently updated other", current.PackageRef.Name)
+	}
+
+	packages, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(packages) != 2 || packages[0].PackageRef.Name != "app" || len(packages[0].Dependencies) != 5 {
+		t.Errorf("Got %d packages, expected app and other with dependencies", len(packages))
+	}
+
+	dependents, err := store.FindDependents(ctx, "qs", "")
+	if err != nil {
+		t.Fatalf("Find dependents error: %v", err)
+	}
+	expected := []domain.Dependent{
+		{Package: ref("app", "1.0.0"), Version: "6.14.0", Relation: "INDIRECT", Depth: 2},
+		{Package: ref("other", "2.0.0"), Version: "6.14.0", Relation: "DIRECT", Depth: 1},
+	}
+	if !slices.Equal(dependents, expected) {
+		t.Errorf("Got dependents %v, expected %v", dependents, expected)
+	}
+	if dependents, err := store.FindDependents(ctx, "qs", "1.0.0"); err != nil || len(dependents) != 0 {
+		t.Errorf("Got dependents %v (err %v), expected none for unknown version", dependents, err)
+	}
+
+	if err := store.DeleteByName(ctx, "app"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if err := store.DeleteByName(ctx, "app"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Got error %v, expected ErrNotFound", err)
+	}
+	if edges, err := store.ListEdges(ctx, "app"); err != nil || len(edges) != 0 {
+		t.Errorf("Got edges %v (err %v), expected none after delete", edges, err)
+	}
+	dependents, err = store.FindDependents(ctx, "qs", "")
+	if err != nil {
+		t.Fatalf("Find dependents error: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].Package.Name != "other" {
+		t.Errorf("Got dependents %v, expected other only", dependents)
+	}
+}
+
+func testDependents(t *testing.T, store Store) {
+	ctx := context.Background()
+	save(t, store, Fixture(), nil)
+	save(t, store, &domain.Package{
+		PackageRef: ref("other", "2.0.0"),
+		LastUpdatedAt: baseTime.Add(time.Hour),
+		Dependencies: []domain.DependencyNode{
+			{Name: "other", Version: "2.0.0", Relation: "SELF"},
+			{Name: "qs", Version: "6.14.0", Relation: "DIRECT", Depth: 1, FanIn: 1},
+			{Name: "qs", Version: "5.0.0", Relation: "INDIRECT", Depth: 2, FanIn: 1},
+		},
+	}, nil)
+
+	tests := []struct {
+		name string
+		dependency string
+		version string
+		expected []domain.Dependent
+	}{
+		{
+			name: "every version in every package",
+			dependency: "qs",
+			expected: []domain.Dependent{
+				{Package: ref("app", "1.0.0"), Version: "6.14.0", Relation: "INDIRECT", Depth: 2},
+				{Package: ref("other", "2.0.0"), Version: "5.0.0", Relation: "INDIRECT", Depth: 2},
+				{Package: ref("other", "2.0.0"), Version: "6.14.0", Relation: "DIRECT", Depth: 1},
+			},
+		},
+		{
+			name: "specific version",
+			dependency: "qs",
+			version: "5.0.0",
+			expected: []domain.Dependent{
+				{Package: ref("other", "2.0.0"), Version: "5.0.0", Relation: "INDIRECT", Depth: 2},
+			},
+		},
+		{
+			name: "single package",
+			dependency: "express",
+			expected: []domain.Dependent{
+				{Package: ref("app", "1.0.0"), Version: "5.2.1", Relation: "DIRECT", Depth: 1},
+			},
+		},
+		{name: "unknown version", dependency: "qs", version: "1.0.0"},
+		{name: "missing dependency", dependency: "lodash"},
+		{name: "names are case sensitive", dependency: "debug"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dependents, err := store.FindDependents(ctx, tt.dependency, tt.version)
+			if err != nil {
+				t.Fatalf("Find dependents error: %v", err)
+			}
+			if !slices.Equal(dependents, tt.expected) {
+				t.Errorf("Got dependents %v, expected %v", dependents, tt.expected)
+			}
+		})
+	}
+}
+
+func testHistory(t *testing.T, store Store) {
+	ctx := context.Background()
+	save(t, store, Fixture(), nil)
+	refreshed := Fixture()
+	refreshed.LastUpdatedAt = baseTime.Add(time.Hour)
+	refreshed.Dependencies = refreshed.Dependencies[:3]
+	refreshed.Dependencies[2].Score = float(3.1)
+	changes := []domain.Change{
+		{Kind: domain.ChangeRemoved, Dependency: "Debug", From: "2.6.9"},
+		{Kind: domain.ChangeScore, Dependency: "qs", From: "4.2", To: "3.1"},
+	}
+	save(t, store, refreshed, changes)
+
+	pkg, err := store.GetByName(ctx, "app", domain.NodeQuery{})
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if pkg.Page.Total != 3 {
+		t.Errorf("Got %d nodes after refresh, expected 3", pkg.Page.Total)
+	}
+
+	snapshots, err := store.ListSnapshots(ctx, "app", 10)
+	if err != nil {
+		t.Fatalf("List snapshots error: %v", err)
+	}
+	if len(snapshots) != 2 || !snapshots[0].TakenAt.Equal(baseTime.Add(time.Hour)) || !slices.Equal(snapshots[0].Changes, changes) || len(snapshots[1].Changes) != 0 {
+		t.Fatalf("Got snapshots %+v, expected newest first with changes", snapshots)
+	}
+	if limited, err := store.ListSnapshots(ctx, "", 1); err != nil || len(limited) != 1 {
+		t.Errorf("Got %d snapshots (err %v), expected limit of 1", len(limited), err)
+	}
+
+	points, err := store.ListScoreHistory(ctx, "app", "qs")
+	if err != nil {
+		t.Fatalf("Score history error: %v", err)
+	}
+	if len(points) != 2 || *points[0].Score != 4.2 || *points[1].Score != 3.1 || points[1].Version != "6.14.0" {
+		t.Errorf("Got score history %+v, expected 4.2 then 3.1", points)
+	}
+}
+
+func testPruneSnapshots(t *testing.T, store Store) {
+	ctx := context.Background()
+	for i := range 4 {
+		pkg := Fixture()
+		pkg.LastUpdatedAt = baseTime.Add(time.Duration(i) * time.Hour)
+		save(t, store, pkg, []domain.Change{{Kind: domain.ChangeAdded, Dependency: fmt.Sprintf("dep-%d", i), To: "1.0.0"}})
+	}
+	other := Fixture()
+	other.PackageRef = ref("other", "1.0.0")
+	save(t, store, other, nil)
+
+	if err := store.PruneSnapshots(ctx, "app", 2); err != nil {
+		t.Fatalf("Prune snapshots error: %v", err)
+	}
+	snapshots, err := store.ListSnapshots(ctx, "app", 10)
+	if err != nil {
+		t.Fatalf("List snapshots error: %v", err)
+	}
+	if len(snapshots) != 2 || !snapshots[0].TakenAt.Equal(baseTime.Add(3*time.Hour)) || !snapshots[1].TakenAt.Equal(baseTime.Add(2*time.Hour)) {
+		t.Fatalf("Got snapshots %+v, expected the 2 newest", snapshots)
+	}
+	if snapshots[1].Changes[0].Dependency != "dep-2" {
+		t.Errorf("Got changes %+v, expected changes of the kept snapshot", snapshots[1].Changes)
+	}
+	if points, err := store.ListScoreHistory(ctx, "app", "qs"); err != nil || len(points) != 2 {
+		t.Errorf("Got %d score points (err %v), expected nodes of pruned snapshots removed", len(points), err)
+	}
+	if kept, err := store.ListSnapshots(ctx, "other", 10); err != nil || len(kept) != 1 {
+		t.Errorf("Got %d snapshots of other (err %v), expected other packages untouched", len(kept), err)
+	}
+	if err := store.PruneSnapshots(ctx, "app", 5); err != nil {
+		t.Errorf("Prune snapshots error: %v", err)
+	}
+}
+
+func testAlerts(t *testing.T, store Store) {
+	ctx := context.Background()
+	alerts := []domain.Alert{
+		{PackageName: "app", Kind: domain.AlertNewAdvisory, Dependency: "qs", Message: "New advisory", CreatedAt: baseTime},
+		{PackageName: "app", Kind: domain.AlertScoreDropped, Dependency: "express", Message: "Score dropped", CreatedAt: baseTime.Add(time.Minute)},
+	}
+	if err := store.SaveAlerts(ctx, alerts); err != nil {
+		t.Fatalf("Save alerts error: %v", err)
+	}
+	if alerts[0].ID == 0 || alerts[1].ID == 0 || alerts[0].ID == alerts[1].ID {
+		t.Fatalf("Got alert ids %d and %d, expected distinct ids", alerts[0].ID, alerts[1].ID)
+	}
+
+	if err := store.AcknowledgeAlert(ctx, alerts[1].ID); err != nil {
+		t.Fatalf("Acknowledge error: %v", err)
+	}
+	if err := store.AcknowledgeAlert(ctx, alerts[1].ID+100); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Errorf("Got error %v, expected ErrAlertNotFound", err)
+	}
+
+	open, err := store.ListAlerts(ctx, false, time.Time{})
+	if err != nil {
+		t.Fatalf("List alerts error: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != alerts[0].ID || open[0].AcknowledgedAt != nil {
+		t.Errorf("Got alerts %+v, expected unacknowledged advisory only", open)
+	}
+	all, err := store.ListAlerts(ctx, true, time.Time{})
+	if err != nil {
+		t.Fatalf("List alerts error: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != alerts[1].ID || all[0].AcknowledgedAt == nil || all[0].Kind != domain.AlertScoreDropped {
+		t.Errorf("Got alerts %+v, expected newest acknowledged alert first", all)
+	}
+	recent, err := store.ListAlerts(ctx, true, baseTime.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("List alerts error: %v", err)
+	}
+	if len(recent) != 1 || recent[0].ID != alerts[1].ID {
+		t.Errorf("Got alerts %+v, expected only the alert created since the cutoff", recent)
+	}
+}
+
+func testDigests(t *testing.T, store Store) {
+	ctx := context.Background()
+	sentAt, err := store.LastDigestSent(ctx)
+	if err != nil {
+		t.Fatalf("Last digest error: %v", err)
+	}
+	if sentAt != nil {
+		t.Errorf("Got last digest %v, expected none", sentAt)
+	}
+
+	for _, at := range []time.Time{baseTime, baseTime.Add(24 * time.Hour)} {
+		if err := store.SaveDigestSent(ctx, at); err != nil {
+			t.Fatalf("Save digest error: %v", err)
+		}
+	}
+	sentAt, err = store.LastDigestSent(ctx)
+	if err != nil {
+		t.Fatalf("Last digest error: %v", err)
+	}
+	if sentAt == nil || !sentAt.Equal(baseTime.Add(24*time.Hour)) {
+		t.Errorf("Got last digest %v, expected %v", sentAt, baseTime.Add(24*time.Hour))
+	}
+}
+
+func testWebhooks(t *testing.T, store Store) {
+	ctx := context.Background()
+	webhook := &domain.Webhook{
+		URL: "https://example.com/hook",
+		Secret: "secret",
+		Events: []domain.EventType{domain.EventPackageRefreshed, domain.EventScoreDropped},
+		Format: domain.WebhookFormatJSON,
+		CreatedAt: baseTime,
+	}
+	if err := store.CreateWebhook(ctx, webhook); err != nil {
+		t.Fatalf("Create webhook error: %v", err)
+	}
+	webhooks, err := store.ListWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("List webhooks error: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].ID != webhook.ID || !slices.Equal(webhooks[0].Events, webhook.Events) || !webhooks[0].CreatedAt.Equal(baseTime) {
+		t.Errorf("Got webhooks %+v, expected %+v", webhooks, webhook)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		delivery := &domain.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event: domain.EventPackageRefreshed,
+			Attempt: attempt,
+			StatusCode: 500 - 300*(attempt-1),
+			Success: attempt == 2,
+			DeliveredAt: baseTime.Add(time.Duration(attempt) * time.Minute),
+		}
+		if err := store.SaveDelivery(ctx, delivery); err != nil {
+			t.Fatalf("Save delivery error: %v", err)
+		}
+		if delivery.ID == 0 {
+			t.Errorf("Got no delivery id")
+		}
+	}
+	deliveries, err := store.ListDeliveries(ctx, webhook.ID)
+	if err != nil {
+		t.Fatalf("List deliveries error: %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Attempt != 2 || !deliveries[0].Success || deliveries[1].StatusCode != 500 {
+		t.Errorf("Got deliveries %+v, expected newest successful attempt first", deliveries)
+	}
+	if _, err := store.ListDeliveries(ctx, webhook.ID+100); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("Got error %v, expected ErrWebhookNotFound", err)
+	}
+
+	if err := store.DeleteWebhook(ctx, webhook.ID); err != nil {
+		t.Fatalf("Delete webhook error: %v", err)
+	}
+	if err := store.DeleteWebhook(ctx, webhook.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("Got error %v, expected ErrWebhookNotFound", err)
+	}
+}
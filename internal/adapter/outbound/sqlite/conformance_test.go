@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Store {
+		db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+		if err != nil {
+			t.Fatalf("Open db error: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		db.SetMaxOpenConns(1)
+		repo, err := NewRepository(db)
+		if err != nil {
+			t.Fatalf("Repository init error: %v", err)
+		}
+		return repo
+	})
+}
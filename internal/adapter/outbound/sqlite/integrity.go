@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+type IntegrityIssue struct {
+	Check string
+	Count int64
+	Repaired bool
+}
+
+var integrityChecks = []struct {
+	name string
+	table string
+	where string
+}{
+	{"nodes of deleted packages", "dependency_nodes", `package_id NOT IN (SELECT id FROM packages)`},
+	{"nodes of missing versions", "dependency_nodes", `version_id NOT IN (SELECT id FROM package_versions)`},
+	{"edges of deleted packages", "dependency_edges", `package_id NOT IN (SELECT id FROM packages)`},
+	{"edges of missing versions", "dependency_edges", `from_version_id NOT IN (SELECT id FROM package_versions) OR to_version_id NOT IN (SELECT id FROM package_versions)`},
+	{"snapshot nodes of missing snapshots", "snapshot_nodes", `snapshot_id NOT IN (SELECT id FROM snapshots)`},
+	{"snapshot changes of missing snapshots", "snapshot_changes", `snapshot_id NOT IN (SELECT id FROM snapshots)`},
+	{"deliveries of deleted webhooks", "webhook_deliveries", `webhook_id NOT IN (SELECT id FROM webhooks)`},
+}
+
+func (r *Repository) CheckIntegrity(ctx context.Context, repair bool) ([]IntegrityIssue, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Begin Transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	var issues []IntegrityIssue
+	rows, err := tx.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("Integrity check error: %w", err)
+	}
+	for rows.Next() {
+		var message string
+		if err := rows.Scan(&message); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("Integrity check scan error: %w", err)
+		}
+		if message != "ok" {
+			issues = append(issues, IntegrityIssue{Check: message, Count: 1})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Integrity check iteration error: %w", err)
+	}
+
+	for _, check := range integrityChecks {
+		issue := IntegrityIssue{Check: check.name}
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM `+check.table+` WHERE `+check.where,
+		).Scan(&issue.Count); err != nil {
+			return nil, fmt.Errorf("Check %s error: %w", check.name, err)
+		}
+		if issue.Count == 0 {
+			continue
+		}
+		if repair {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+check.table+` WHERE `+check.where); err != nil {
+				return nil, fmt.Errorf("Repair %s error: %w", check.name, err)
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+
+	var violations int64
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_foreign_key_check`).Scan(&violations); err != nil {
+		return nil, fmt.Errorf("Foreign key check error: %w", err)
+	}
+	if violations > 0 {
+		issues = append(issues, IntegrityIssue{Check: "foreign key violations", Count: violations})
+	}
+
+	if repair {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("Commit error: %w", err)
+		}
+	}
+	return issues, nil
+}
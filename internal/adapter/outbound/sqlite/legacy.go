@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const legacyProjectPrefix = "legacy:"
+
+var legacyVersionColumns = []struct {
+	name string
+	fallback string
+}{
+	{"advisories", "''"},
+	{"severity", "NULL"},
+	{"license", "NULL"},
+	{"project_key", "NULL"},
+	{"latest_version", "NULL"},
+	{"major_behind", "0"},
+	{"minor_behind", "0"},
+	{"patch_behind", "0"},
+	{"deprecated", "0"},
+	{"deprecation_reason", "NULL"},
+	{"published_at", "NULL"},
+	{"latest_release_at", "NULL"},
+}
+
+func migrateLegacyLayout(ctx context.Context, db *sql.DB, catalog string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Begin Transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	legacy, err := hasColumn(ctx, tx, "dependency_nodes", "name")
+	if err != nil || !legacy {
+		return err
+	}
+
+	if err := addMissingColumn(ctx, tx, "packages", "health", "REAL"); err != nil {
+		return err
+	}
+	if exists, err := hasTable(ctx, tx, "projects"); err != nil {
+		return err
+	} else if exists {
+		if err := addMissingColumn(ctx, tx, "projects", "score", "REAL"); err != nil {
+			return err
+		}
+		if err := addMissingColumn(ctx, tx, "projects", "scorecard_date", "DATETIME"); err != nil {
+			return err
+		}
+	}
+
+	legacyEdges, err := hasColumn(ctx, tx, "dependency_edges", "from_name")
+	if err != nil {
+		return err
+	}
+	statements := []string{
+		`DROP INDEX IF EXISTS dependency_nodes_name`,
+		`ALTER TABLE dependency_nodes RENAME TO legacy_dependency_nodes`,
+	}
+	if legacyEdges {
+		statements = append(statements,
+			`DROP INDEX IF EXISTS dependency_edges_package_id`,
+			`ALTER TABLE dependency_edges RENAME TO legacy_dependency_edges`,
+		)
+	}
+	statements = append(statements, catalog)
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("Legacy layout rename error: %w", err)
+		}
+	}
+
+	columns := make([]string, len(legacyVersionColumns))
+	values := make([]string, len(legacyVersionColumns))
+	for i, column := range legacyVersionColumns {
+		columns[i] = column.name
+		values[i] = column.fallback
+		if ok, err := hasColumn(ctx, tx, "legacy_dependency_nodes", column.name); err != nil {
+			return err
+		} else if ok {
+			values[i] = column.name
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO package_versions (name, version, `+strings.Join(columns, ", ")+`)
+		 SELECT name, version, `+strings.Join(values, ", ")+`
+		 FROM legacy_dependency_nodes
+		 WHERE id IN (SELECT MAX(id) FROM legacy_dependency_nodes GROUP BY name, version)`,
+	); err != nil {
+		return fmt.Errorf("Migrate versions error: %w", err)
+	}
+
+	ok, err := hasColumn(ctx, tx, "legacy_dependency_nodes", "project_key")
+	if err != nil {
+		return err
+	}
+	if ok {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO projects (project_key, score, fetched_at)
+			 SELECT project_key, MAX(score), ?
+			 FROM legacy_dependency_nodes
+			 WHERE project_key IS NOT NULL
+			 GROUP BY project_key
+			 ON CONFLICT (project_key) DO UPDATE SET score = excluded.score`,
+			time.Time{},
+		); err != nil {
+			return fmt.Errorf("Migrate project scores error: %w", err)
+		}
+	}
+
+	unassigned := "1 = 1"
+	if ok {
+		unassigned = "project_key IS NULL"
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO projects (project_key, score, fetched_at)
+		 SELECT ? || name, score, ?
+		 FROM legacy_dependency_nodes
+		 WHERE id IN (SELECT MAX(id) FROM legacy_dependency_nodes WHERE score IS NOT NULL AND `+unassigned+` GROUP BY name)
+		 ON CONFLICT (project_key) DO NOTHING`,
+		legacyProjectPrefix, time.Time{},
+	); err != nil {
+		return fmt.Errorf("Migrate unassigned scores error: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE package_versions SET project_key = ? || name
+		 WHERE project_key IS NULL AND ? || name IN (SELECT project_key FROM projects)`,
+		legacyProjectPrefix, legacyProjectPrefix,
+	); err != nil {
+		return fmt.Errorf("Assign legacy projects error: %w", err)
+	}
+
+	depth, fanIn := "1", "1"
+	if ok, err := hasColumn(ctx, tx, "legacy_dependency_nodes", "depth"); err != nil {
+		return err
+	} else if ok {
+		depth, fanIn = "l.depth", "l.fan_in"
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO dependency_nodes (id, package_id, version_id, relation, depth, fan_in)
+		 SELECT l.id, l.package_id, v.id, l.relation, `+depth+`, `+fanIn+`
+		 FROM legacy_dependency_nodes l
+		 JOIN packages p ON p.id = l.package_id
+		 JOIN package_versions v ON v.name = l.name AND v.version = l.version`,
+	); err != nil {
+		return fmt.Errorf("Migrate nodes error: %w", err)
+	}
+
+	if legacyEdges {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO dependency_edges (package_id, from_version_id, to_version_id)
+			 SELECT e.package_id, f.id, t.id
+			 FROM legacy_dependency_edges e
+			 JOIN packages p ON p.id = e.package_id
+			 JOIN package_versions f ON f.name = e.from_name AND f.version = e.from_version
+			 JOIN package_versions t ON t.name = e.to_name AND t.version = e.to_version`,
+		); err != nil {
+			return fmt.Errorf("Migrate edges error: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DROP TABLE legacy_dependency_edges`); err != nil {
+			return fmt.Errorf("Drop legacy edges error: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DROP TABLE legacy_dependency_nodes`); err != nil {
+		return fmt.Errorf("Drop legacy nodes error: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func hasTable(ctx context.Context, tx *sql.Tx, table string) (bool, error) {
+	var count int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table,
+	).Scan(&count); err != nil {
+		return false, fmt.Errorf("Table lookup error: %w", err)
+	}
+	return count > 0, nil
+}
+
+func hasColumn(ctx context.Context, tx *sql.Tx, table, column string) (bool, error) {
+	var count int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column,
+	).Scan(&count); err != nil {
+		return false, fmt.Errorf("Column lookup error: %w", err)
+	}
+	return count > 0, nil
+}
+
+func addMissingColumn(ctx context.Context, tx *sql.Tx, table, column, definition string) error {
+	exists, err := hasColumn(ctx, tx, table, column)
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition)); err != nil {
+		return fmt.Errorf("Add column %s.%s error: %w", table, column, err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlstore"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+func NewMigrator(db *sql.DB) (*sqlstore.Migrator, error) {
+	return sqlstore.NewMigrator(db, migrationFiles, sqlstore.SQLite, func(ctx context.Context, initial string) error {
+		return migrateLegacyLayout(ctx, db, initial)
+	}, nil)
+}
@@ -0,0 +1,73 @@
+CREATE TABLE IF NOT EXISTS packages (
+	id				INTEGER PRIMARY KEY AUTOINCREMENT,
+	name 			TEXT NOT NULL,
+	version			TEXT NOT NULL,
+	last_updated_at DATETIME NOT NULL,
+	health			REAL,
+	UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+	project_key		TEXT PRIMARY KEY,
+	description		TEXT NOT NULL DEFAULT '',
+	homepage		TEXT NOT NULL DEFAULT '',
+	project_license	TEXT NOT NULL DEFAULT '',
+	stars			INTEGER NOT NULL DEFAULT 0,
+	forks			INTEGER NOT NULL DEFAULT 0,
+	open_issues		INTEGER NOT NULL DEFAULT 0,
+	oss_fuzz		BOOLEAN NOT NULL DEFAULT 0,
+	score			REAL,
+	scorecard_date	DATETIME,
+	fetched_at		DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS package_versions (
+	id					INTEGER PRIMARY KEY AUTOINCREMENT,
+	name				TEXT NOT NULL,
+	version				TEXT NOT NULL,
+	advisories			TEXT NOT NULL DEFAULT '',
+	severity			REAL,
+	license				TEXT,
+	project_key			TEXT,
+	latest_version		TEXT,
+	major_behind		INTEGER NOT NULL DEFAULT 0,
+	minor_behind		INTEGER NOT NULL DEFAULT 0,
+	patch_behind		INTEGER NOT NULL DEFAULT 0,
+	deprecated			BOOLEAN NOT NULL DEFAULT 0,
+	deprecation_reason	TEXT,
+	published_at		DATETIME,
+	latest_release_at	DATETIME,
+	UNIQUE (name, version)
+);
+
+CREATE TABLE IF NOT EXISTS dependency_nodes (
+	id			INTEGER PRIMARY KEY AUTOINCREMENT,
+	package_id	INTEGER NOT NULL REFERENCES packages(id) ON DELETE CASCADE,
+	version_id	INTEGER NOT NULL REFERENCES package_versions(id),
+	relation	TEXT NOT NULL,
+	depth		INTEGER NOT NULL DEFAULT 1,
+	fan_in		INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS dependency_nodes_package_id ON dependency_nodes (package_id);
+CREATE INDEX IF NOT EXISTS dependency_nodes_version_id ON dependency_nodes (version_id);
+
+CREATE TABLE IF NOT EXISTS dependency_edges (
+	id				INTEGER PRIMARY KEY AUTOINCREMENT,
+	package_id		INTEGER NOT NULL REFERENCES packages(id) ON DELETE CASCADE,
+	from_version_id	INTEGER NOT NULL REFERENCES package_versions(id),
+	to_version_id	INTEGER NOT NULL REFERENCES package_versions(id)
+);
+
+CREATE INDEX IF NOT EXISTS dependency_edges_package ON dependency_edges (package_id);
+
+CREATE VIEW IF NOT EXISTS graph_nodes AS
+SELECT
+	n.id, n.package_id, n.version_id, v.name, v.version, n.relation, p.score, v.advisories, v.severity, v.license,
+	n.depth, n.fan_in, v.project_key, v.latest_version, v.major_behind, v.minor_behind, v.patch_behind,
+	v.deprecated, v.deprecation_reason, v.published_at, v.latest_release_at,
+	p.description, p.homepage, p.project_license, p.stars, p.forks, p.open_issues, p.oss_fuzz, p.scorecard_date,
+	p.fetched_at AS project_fetched_at
+FROM dependency_nodes n
+JOIN package_versions v ON v.id = n.version_id
+LEFT JOIN projects p ON p.project_key = v.project_key;
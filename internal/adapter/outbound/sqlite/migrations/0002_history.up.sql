@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS snapshots (
+	id				INTEGER PRIMARY KEY AUTOINCREMENT,
+	package_name	TEXT NOT NULL,
+	version			TEXT NOT NULL,
+	taken_at		DATETIME NOT NULL,
+	health			REAL
+);
+
+CREATE INDEX IF NOT EXISTS snapshots_package_name ON snapshots (package_name, taken_at);
+
+CREATE TABLE IF NOT EXISTS snapshot_nodes (
+	id			INTEGER PRIMARY KEY AUTOINCREMENT,
+	snapshot_id	INTEGER NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+	name		TEXT NOT NULL,
+	version		TEXT NOT NULL,
+	relation	TEXT NOT NULL,
+	score		REAL,
+	advisories	TEXT NOT NULL DEFAULT '',
+	severity	REAL,
+	license		TEXT,
+	depth		INTEGER NOT NULL DEFAULT 1,
+	fan_in		INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS snapshot_changes (
+	id			INTEGER PRIMARY KEY AUTOINCREMENT,
+	snapshot_id	INTEGER NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+	kind		TEXT NOT NULL,
+	dependency	TEXT NOT NULL,
+	from_value	TEXT NOT NULL DEFAULT '',
+	to_value	TEXT NOT NULL DEFAULT ''
+);
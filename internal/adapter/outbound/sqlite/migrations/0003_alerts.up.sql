@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS alerts (
+	id				INTEGER PRIMARY KEY AUTOINCREMENT,
+	package_name	TEXT NOT NULL,
+	kind			TEXT NOT NULL,
+	dependency		TEXT NOT NULL,
+	message			TEXT NOT NULL,
+	created_at		DATETIME NOT NULL,
+	acknowledged_at	DATETIME
+);
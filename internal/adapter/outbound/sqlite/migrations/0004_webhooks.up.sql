@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+	id			INTEGER PRIMARY KEY AUTOINCREMENT,
+	url			TEXT NOT NULL,
+	secret		TEXT NOT NULL,
+	events		TEXT NOT NULL DEFAULT '',
+	format		TEXT NOT NULL,
+	created_at	DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id				INTEGER PRIMARY KEY AUTOINCREMENT,
+	webhook_id		INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+	event			TEXT NOT NULL,
+	attempt			INTEGER NOT NULL,
+	status_code		INTEGER NOT NULL,
+	error			TEXT NOT NULL DEFAULT '',
+	success			BOOLEAN NOT NULL,
+	delivered_at	DATETIME NOT NULL
+);
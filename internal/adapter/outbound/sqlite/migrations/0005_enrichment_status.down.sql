@@ -0,0 +1,15 @@
+DROP VIEW IF EXISTS graph_nodes;
+
+ALTER TABLE dependency_nodes DROP COLUMN status_message;
+ALTER TABLE dependency_nodes DROP COLUMN status;
+
+CREATE VIEW graph_nodes AS
+SELECT
+	n.id, n.package_id, n.version_id, v.name, v.version, n.relation, p.score, v.advisories, v.severity, v.license,
+	n.depth, n.fan_in, v.project_key, v.latest_version, v.major_behind, v.minor_behind, v.patch_behind,
+	v.deprecated, v.deprecation_reason, v.published_at, v.latest_release_at,
+	p.description, p.homepage, p.project_license, p.stars, p.forks, p.open_issues, p.oss_fuzz, p.scorecard_date,
+	p.fetched_at AS project_fetched_at
+FROM dependency_nodes n
+JOIN package_versions v ON v.id = n.version_id
+LEFT JOIN projects p ON p.project_key = v.project_key;
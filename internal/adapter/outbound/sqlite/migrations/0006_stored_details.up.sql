@@ -0,0 +1,8 @@
+ALTER TABLE projects ADD COLUMN scorecard_checks TEXT;
+
+CREATE TABLE IF NOT EXISTS advisories (
+	advisory_id	TEXT PRIMARY KEY,
+	title		TEXT NOT NULL DEFAULT '',
+	cvss3_score	REAL,
+	fetched_at	DATETIME NOT NULL
+);
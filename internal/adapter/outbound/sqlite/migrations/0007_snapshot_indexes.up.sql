@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS snapshot_nodes_snapshot_id ON snapshot_nodes (snapshot_id);
+CREATE INDEX IF NOT EXISTS snapshot_nodes_name ON snapshot_nodes (name);
+CREATE INDEX IF NOT EXISTS snapshot_changes_snapshot_id ON snapshot_changes (snapshot_id);
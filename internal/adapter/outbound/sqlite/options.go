@@ -0,0 +1,5 @@
+package sqlite
+
+func DSN(path string) string {
+	return path + "?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL&_synchronous=NORMAL&_cache_size=-32000"
+}
@@ -1,62 +1,443 @@
 package sqlite
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/sqlstore"
 	"github.com/JCzapla/dep-dashboard/internal/domain"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-func TestBuildFilters(t *testing.T) {
+var safeClauseToken = regexp.MustCompile(`^(name|version|relation|license|score|severity|depth|latest_version|major_behind|minor_behind|patch_behind|deprecated|status|=|!=|<|<=|>|>=|\?|AND|OR|IN|LIKE|ESCAPE|'\\'|IS|NOT|NULL)$`)
+
+func FuzzBuildFilters(f *testing.F) {
+	f.Add("name", "eq", "express", "score", "gte", "5", true)
+	f.Add("relation", "in", "DIRECT,SELF", "license", "isnull", "", false)
+	f.Add("version", "prefix", "1.%_\\", "depth", "lt", "2", true)
+	f.Add("name", "like", "'; DROP TABLE packages; --", "severity", "ne", "7.5", false)
+	f.Add("name) OR (1=1", "eq", "x", "score", "gte", "NaN", true)
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		f.Fatalf("Open db error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := NewRepository(db); err != nil {
+		f.Fatalf("Repository init error: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, column1, operator1, value1, column2, operator2, value2 string, or bool) {
+		logic := domain.LogicAnd
+		if or {
+			logic = domain.LogicOr
+		}
+		filters := []domain.Filter{
+			{Column: column1, Operator: domain.Operator(operator1), Value: value1},
+			{Logic: logic, Filters: []domain.Filter{
+				{Column: column2, Operator: domain.Operator(operator2), Value: value2},
+				{Column: column1, Operator: domain.Operator(operator2), Value: value1},
+			}},
+		}
+
+		clause, args, err := sqlstore.BuildFilters(filters, sqlstore.SQLite)
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ", ",", " ").Replace(clause))
+		for _, token := range fields {
+			if !safeClauseToken.MatchString(token) {
+				t.Fatalf("Unexpected token %q in clause %q", token, clause)
+			}
+		}
+		if placeholders := strings.Count(clause, "?"); placeholders != len(args) {
+			t.Fatalf("Got %d placeholders and %d args in clause %q", placeholders, len(args), clause)
+		}
+
+		query := `SELECT COUNT(*) FROM graph_nodes WHERE package_id = ?` + clause
+		var count int
+		if err := db.QueryRow(query, append([]any{1}, args...)...).Scan(&count); err != nil {
+			t.Fatalf("Query %q failed: %v", query, err)
+		}
+	})
+}
+
+func TestMigrateLegacyLayout(t *testing.T) {
 	tests := []struct {
 		name string
-		filters []domain.Filter
-		expectedClause string
-		expectedArgs []any
-		Error string
+		statements []string
+		expectedNodes int
+		expectedScores map[string]float64
+		expectedEdges int
 	}{
 		{
-			name: "eq operator",
-			filters: []domain.Filter{
-				{Column: "name", Operator: domain.FilterEq, Value: "express"},
-			},
-			expectedClause: " AND name = ?",
-			expectedArgs: []any{"express"},
-		},
-		{
-			name: "gt operator",
-			filters: []domain.Filter{
-				{Column: "score", Operator: domain.FilterGte, Value: "5.5"},
+			name: "baseline layout",
+			statements: []string{
+				`CREATE TABLE packages (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, version TEXT NOT NULL, last_updated_at DATETIME NOT NULL, UNIQUE (name))`,
+				`CREATE TABLE dependency_nodes (id INTEGER PRIMARY KEY AUTOINCREMENT, package_id INTEGER NOT NULL REFERENCES packages(id) ON DELETE CASCADE, name TEXT NOT NULL, version TEXT NOT NULL, relation TEXT NOT NULL, score REAL)`,
+				`INSERT INTO packages (name, version, last_updated_at) VALUES ('express', '5.2.1', '2026-01-01T12:00:00Z')`,
+				`INSERT INTO dependency_nodes (package_id, name, version, relation, score) VALUES (1, 'express', '5.2.1', 'SELF', 8.4), (1, 'qs', '6.14.0', 'INDIRECT', 6.1)`,
 			},
-			expectedClause: " AND score >= ?",
-			expectedArgs: []any{5.5},
+			expectedNodes: 2,
+			expectedScores: map[string]float64{"express": 8.4, "qs": 6.1},
 		},
 		{
-			name: "injection",
-			filters: []domain.Filter{
-				{Column: "injected", Operator: domain.FilterEq, Value: "x"},
+			name: "per package nodes with edges",
+			statements: []string{
+				`CREATE TABLE packages (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, version TEXT NOT NULL, last_updated_at DATETIME NOT NULL, health REAL, UNIQUE (name))`,
+				`CREATE TABLE dependency_nodes (id INTEGER PRIMARY KEY AUTOINCREMENT, package_id INTEGER NOT NULL REFERENCES packages(id) ON DELETE CASCADE, name TEXT NOT NULL, version TEXT NOT NULL, relation TEXT NOT NULL, score REAL, advisories TEXT NOT NULL DEFAULT '', severity REAL, license TEXT, depth INTEGER NOT NULL DEFAULT 1, fan_in INTEGER NOT NULL DEFAULT 1, project_key TEXT)`,
+				`CREATE INDEX dependency_nodes_name ON dependency_nodes (name, version)`,
+				`CREATE TABLE projects (project_key TEXT PRIMARY KEY, description TEXT NOT NULL DEFAULT '', homepage TEXT NOT NULL DEFAULT '', project_license TEXT NOT NULL DEFAULT '', stars INTEGER NOT NULL DEFAULT 0, forks INTEGER NOT NULL DEFAULT 0, open_issues INTEGER NOT NULL DEFAULT 0, oss_fuzz BOOLEAN NOT NULL DEFAULT 0, fetched_at DATETIME NOT NULL)`,
+				`CREATE TABLE dependency_edges (id INTEGER PRIMARY KEY AUTOINCREMENT, package_id INTEGER NOT NULL REFERENCES packages(id) ON DELETE CASCADE, from_name TEXT NOT NULL, from_version TEXT NOT NULL, to_name TEXT NOT NULL, to_version TEXT NOT NULL)`,
+				`CREATE INDEX dependency_edges_package_id ON dependency_edges (package_id)`,
+				`INSERT INTO packages (name, version, last_updated_at, health) VALUES ('express', '5.2.1', '2026-01-01T12:00:00Z', 7.2)`,
+				`INSERT INTO dependency_nodes (package_id, name, version, relation, score, depth, fan_in, project_key) VALUES (1, 'express', '5.2.1', 'SELF', 8.4, 0, 0, NULL), (1, 'qs', '6.14.0', 'DIRECT', 6.1, 1, 1, 'github.com/ljharb/qs')`,
+				`INSERT INTO dependency_edges (package_id, from_name, from_version, to_name, to_version) VALUES (1, 'express', '5.2.1', 'qs', '6.14.0')`,
 			},
-			Error: "Unknown filter error: \"injected\"",
+			expectedNodes: 2,
+			expectedScores: map[string]float64{"express": 8.4, "qs": 6.1},
+			expectedEdges: 1,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			clause, args, err := buildFilters(tt.filters)
-			if err != nil && err.Error() != tt.Error {
-				t.Fatalf("Got error %s, expected %s", err.Error(), tt.Error)
+			db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+			if err != nil {
+				t.Fatalf("Open db error: %v", err)
+			}
+			defer db.Close()
+			db.SetMaxOpenConns(1)
+			for _, statement := range tt.statements {
+				if _, err := db.Exec(statement); err != nil {
+					t.Fatalf("Legacy statement error: %v", err)
+				}
+			}
+
+			repo, err := NewRepository(db)
+			if err != nil {
+				t.Fatalf("Repository init error: %v", err)
+			}
+			ctx := context.Background()
+			pkg, err := repo.GetByName(ctx, "express", domain.NodeQuery{
+				Filters: []domain.Filter{{Column: "name", Operator: domain.FilterEq, Value: "qs"}},
+			})
+			if err != nil {
+				t.Fatalf("Get package error: %v", err)
+			}
+			if pkg.Page.Total != 1 || len(pkg.Dependencies) != 1 {
+				t.Fatalf("Got %d dependencies, expected qs only", pkg.Page.Total)
 			}
 
-			if clause != tt.expectedClause {
-				t.Errorf("Got clause %s, expected %s", clause, tt.expectedClause)
+			all, err := repo.GetByName(ctx, "express", domain.NodeQuery{})
+			if err != nil {
+				t.Fatalf("Get package error: %v", err)
 			}
-			if len(args) != len(tt.expectedArgs) {
-				t.Fatalf("Got args len %d, expected %d", len(args), len(tt.expectedArgs))
+			if len(all.Dependencies) != tt.expectedNodes {
+				t.Errorf("Got %d nodes, expected %d", len(all.Dependencies), tt.expectedNodes)
 			}
-			for i, arg := range args {
-				if arg != tt.expectedArgs[i] {
-					t.Errorf("Got args[%d] %v, expected %v", i, arg, tt.expectedArgs[i])
+			for _, node := range all.Dependencies {
+				expected, ok := tt.expectedScores[node.Name]
+				if !ok || node.Score == nil || *node.Score != expected {
+					t.Errorf("Got %s score %v, expected %v to survive migration", node.Name, node.Score, expected)
 				}
 			}
+			edges, err := repo.ListEdges(ctx, "express")
+			if err != nil {
+				t.Fatalf("List edges error: %v", err)
+			}
+			if len(edges) != tt.expectedEdges {
+				t.Errorf("Got %d edges, expected %d", len(edges), tt.expectedEdges)
+			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestMigrator(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Open db error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Migrator init error: %v", err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Migrate up error: %v", err)
+	}
+	if applied != migrator.Latest() {
+		t.Errorf("Applied %d migrations, expected %d", applied, migrator.Latest())
+	}
+	if applied, err := migrator.Up(ctx); err != nil || applied != 0 {
+		t.Errorf("Second up applied %d migrations (err %v), expected none", applied, err)
+	}
+
+	reverted, err := migrator.Down(ctx, migrator.Latest())
+	if err != nil {
+		t.Fatalf("Migrate down error: %v", err)
+	}
+	if reverted != migrator.Latest() {
+		t.Errorf("Reverted %d migrations, expected %d", reverted, migrator.Latest())
+	}
+	var tables int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT IN ('schema_migrations', 'sqlite_sequence')`,
+	).Scan(&tables); err != nil {
+		t.Fatalf("Table count error: %v", err)
+	}
+	if tables != 0 {
+		t.Errorf("Got %d tables after full down, expected 0", tables)
+	}
+
+	if _, err := NewRepository(db); err != nil {
+		t.Fatalf("Repository init error: %v", err)
+	}
+	if _, err := db.Exec(
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'future', CURRENT_TIMESTAMP)`,
+		migrator.Latest()+1,
+	); err != nil {
+		t.Fatalf("Insert future migration error: %v", err)
+	}
+	if _, err := NewRepository(db); !errors.Is(err, sqlstore.ErrSchemaTooNew) {
+		t.Errorf("Got error %v, expected ErrSchemaTooNew", err)
+	}
+}
+
+func TestSnapshotIndexes(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Open db error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := NewRepository(db); err != nil {
+		t.Fatalf("Repository init error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		query string
+		index string
+	}{
+		{name: "score history", query: `SELECT n.version FROM snapshot_nodes n JOIN snapshots s ON s.id = n.snapshot_id WHERE s.package_name = 'app' AND n.name = 'qs'`, index: "snapshot_nodes_"},
+		{name: "snapshot nodes", query: `SELECT id FROM snapshot_nodes WHERE snapshot_id = 1`, index: "snapshot_nodes_snapshot_id"},
+		{name: "snapshot changes", query: `SELECT id FROM snapshot_changes WHERE snapshot_id IN (1, 2)`, index: "snapshot_changes_snapshot_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := db.Query(`EXPLAIN QUERY PLAN ` + tt.query)
+			if err != nil {
+				t.Fatalf("Explain error: %v", err)
+			}
+			defer rows.Close()
+			var plan []string
+			for rows.Next() {
+				var id, parent, unused int
+				var detail string
+				if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+					t.Fatalf("Plan scan error: %v", err)
+				}
+				plan = append(plan, detail)
+			}
+			if !strings.Contains(strings.Join(plan, "\n"), "INDEX "+tt.index) {
+				t.Errorf("Got plan %q, expected %s index", plan, tt.index)
+			}
+		})
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Open db error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	repo, err := NewRepository(db)
+	if err != nil {
+		t.Fatalf("Repository init error: %v", err)
+	}
+	ctx := context.Background()
+
+	pkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "express", Version: "5.2.1"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "express", Version: "5.2.1", Relation: "SELF"},
+			{Name: "qs", Version: "6.14.0", Relation: "DIRECT"},
+		},
+		Edges: []domain.Edge{{
+			From: domain.PackageRef{Name: "express", Version: "5.2.1"},
+			To: domain.PackageRef{Name: "qs", Version: "6.14.0"},
+		}},
+	}
+	if err := repo.Save(ctx, pkg, nil); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if err := repo.DeleteByName(ctx, "express"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	var nodes int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM dependency_nodes`).Scan(&nodes); err != nil {
+		t.Fatalf("Count nodes error: %v", err)
+	}
+	if nodes != 0 {
+		t.Errorf("Got %d nodes after delete, expected cascade to remove them", nodes)
+	}
+
+	if _, err := db.Exec(`PRAGMA foreign_keys = off`); err != nil {
+		t.Fatalf("Disable foreign keys error: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO dependency_nodes (package_id, version_id, relation) VALUES (42, 1, 'DIRECT')`); err != nil {
+		t.Fatalf("Insert orphan error: %v", err)
+	}
+	if _, err := db.Exec(`PRAGMA foreign_keys = on`); err != nil {
+		t.Fatalf("Enable foreign keys error: %v", err)
+	}
+
+	issues, err := repo.CheckIntegrity(ctx, false)
+	if err != nil {
+		t.Fatalf("Check error: %v", err)
+	}
+	found := make(map[string]int64)
+	for _, issue := range issues {
+		found[issue.Check] = issue.Count
+	}
+	if found["nodes of deleted packages"] != 1 {
+		t.Errorf("Got issues %v, expected one orphaned node", found)
+	}
+
+	if _, err := repo.CheckIntegrity(ctx, true); err != nil {
+		t.Fatalf("Repair error: %v", err)
+	}
+	issues, err = repo.CheckIntegrity(ctx, false)
+	if err != nil {
+		t.Fatalf("Check error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Got issues %v after repair, expected none", issues)
+	}
+	var versions int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM package_versions`).Scan(&versions); err != nil {
+		t.Fatalf("Count versions error: %v", err)
+	}
+	if versions != 2 {
+		t.Errorf("Got %d catalog versions after repair, expected the shared rows to be kept", versions)
+	}
+}
+
+func benchmarkGraph(size int) *domain.Package {
+	root := domain.PackageRef{Name: "monorepo", Version: "1.0.0"}
+	pkg := &domain.Package{PackageRef: root, LastUpdatedAt: time.Now().UTC()}
+	for i := range size {
+		ref := domain.PackageRef{Name: fmt.Sprintf("dep-%d", i), Version: fmt.Sprintf("1.%d.0", i%7)}
+		relation, depth := "INDIRECT", 2+i%5
+		if i == 0 {
+			ref, relation, depth = root, "SELF", 0
+		}
+		score := float64(i % 10)
+		key := fmt.Sprintf("github.com/example/project-%d", i/10)
+		pkg.Dependencies = append(pkg.Dependencies, domain.DependencyNode{
+			Name: ref.Name,
+			Version: ref.Version,
+			Relation: relation,
+			Depth: depth,
+			FanIn: 1 + i%3,
+			License: "MIT",
+			ProjectKey: key,
+			LatestVersion: "2.0.0",
+			Score: &score,
+			Project: &domain.Project{Key: key, Scorecard: domain.Scorecard{Score: score}, FetchedAt: pkg.LastUpdatedAt},
+		})
+		if i > 0 {
+			parent := pkg.Dependencies[i/2]
+			pkg.Edges = append(pkg.Edges, domain.Edge{From: domain.PackageRef{Name: parent.Name, Version: parent.Version}, To: ref})
+		}
+	}
+	return pkg
+}
+
+func TestGetProjectsChunks(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Open db error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := NewRepository(db); err != nil {
+		t.Fatalf("Repository init error: %v", err)
+	}
+	dialect := sqlstore.SQLite
+	dialect.MaxParams = 3
+	store := sqlstore.NewStore(db, dialect)
+	ctx := context.Background()
+
+	pkg := benchmarkGraph(100)
+	if err := store.Save(ctx, pkg, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	var keys []string
+	for i := range 10 {
+		keys = append(keys, fmt.Sprintf("github.com/example/project-%d", i))
+	}
+	keys = append(keys, "github.com/example/missing")
+
+	projects, err := store.GetProjects(ctx, keys)
+	if err != nil {
+		t.Fatalf("GetProjects failed: %v", err)
+	}
+	if len(projects) != 10 {
+		t.Errorf("Got %d projects, expected 10 read across chunks", len(projects))
+	}
+}
+
+func BenchmarkSave(b *testing.B) {
+	perRow := sqlstore.SQLite
+	perRow.MaxParams = 1
+	modes := []struct {
+		name string
+		dsn func(path string) string
+		dialect sqlstore.Dialect
+	}{
+		{name: "per-row", dsn: func(path string) string { return path + "?_foreign_keys=on" }, dialect: perRow},
+		{name: "batched", dsn: DSN, dialect: sqlstore.SQLite},
+	}
+
+	for _, size := range []int{1000, 10000, 50000} {
+		for _, mode := range modes {
+			b.Run(fmt.Sprintf("nodes=%d/%s", size, mode.name), func(b *testing.B) {
+				db, err := sql.Open("sqlite3", mode.dsn(filepath.Join(b.TempDir(), "deps.db")))
+				if err != nil {
+					b.Fatalf("Open db error: %v", err)
+				}
+				defer db.Close()
+				if _, err := NewRepository(db); err != nil {
+					b.Fatalf("Repository init error: %v", err)
+				}
+				store := sqlstore.NewStore(db, mode.dialect)
+				pkg := benchmarkGraph(size)
+				ctx := context.Background()
+
+				for b.Loop() {
+					if err := store.Save(ctx, pkg, nil); err != nil {
+						b.Fatalf("Save failed: %v", err)
+					}
+				}
+				b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N)/float64(size), "µs/node")
+			})
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (s *Store) SaveAlerts(ctx context.Context, alerts []domain.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Begin Transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range alerts {
+		if err := tx.QueryRowContext(ctx,
+			s.rebind(`INSERT INTO alerts (package_name, kind, dependency, message, created_at)
+			 VALUES (?, ?, ?, ?, ?)
+			 RETURNING id`),
+			alerts[i].PackageName,
+			alerts[i].Kind,
+			alerts[i].Dependency,
+			alerts[i].Message,
+			alerts[i].CreatedAt,
+		).Scan(&alerts[i].ID); err != nil {
+			return fmt.Errorf("Insert alert error: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) ListAlerts(ctx context.Context, includeAcknowledged bool, since time.Time) ([]domain.Alert, error) {
+	query := `SELECT id, package_name, kind, dependency, message, created_at, acknowledged_at
+		 FROM alerts`
+	var conditions []string
+	var args []any
+	if !includeAcknowledged {
+		conditions = append(conditions, `acknowledged_at IS NULL`)
+	}
+	if !since.IsZero() {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, since.UTC())
+	}
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("Query alerts error: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []domain.Alert
+	for rows.Next() {
+		var alert domain.Alert
+		var acknowledgedAt sql.NullTime
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.PackageName,
+			&alert.Kind,
+			&alert.Dependency,
+			&alert.Message,
+			&alert.CreatedAt,
+			&acknowledgedAt,
+		); err != nil {
+			return nil, fmt.Errorf("Alert scan error: %w", err)
+		}
+		if acknowledgedAt.Valid {
+			alert.AcknowledgedAt = &acknowledgedAt.Time
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Alert iteration error: %w", err)
+	}
+	return alerts, nil
+}
+
+func (s *Store) AcknowledgeAlert(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx,
+		s.rebind(`UPDATE alerts SET acknowledged_at = COALESCE(acknowledged_at, ?) WHERE id = ?`),
+		time.Now().UTC(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("Acknowledge alert error: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrAlertNotFound
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+func Insert(ctx context.Context, tx *sql.Tx, dialect Dialect, into string, suffix string, rows [][]any) error {
+	return batches(dialect, into, suffix, rows, func(query string, args []any) error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+func InsertReturning(ctx context.Context, tx *sql.Tx, dialect Dialect, into string, suffix string, rows [][]any, scan func(*sql.Rows) error) error {
+	return batches(dialect, into, suffix, rows, func(query string, args []any) error {
+		result, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer result.Close()
+		for result.Next() {
+			if err := scan(result); err != nil {
+				return err
+			}
+		}
+		return result.Err()
+	})
+}
+
+func Select(ctx context.Context, db *sql.DB, dialect Dialect, query string, values []any, scan func(*sql.Rows) error) error {
+	return chunks(dialect, query, values, func(query string, args []any) error {
+		result, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer result.Close()
+		for result.Next() {
+			if err := scan(result); err != nil {
+				return err
+			}
+		}
+		return result.Err()
+	})
+}
+
+func chunks(dialect Dialect, query string, values []any, run func(query string, args []any) error) error {
+	size := max(1, dialect.MaxParams)
+	for start := 0; start < len(values); start += size {
+		chunk := values[start:min(start+size, len(values))]
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ")
+		if err := run(dialect.Rebind(fmt.Sprintf(query, placeholders)), chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func batches(dialect Dialect, into string, suffix string, rows [][]any, run func(query string, args []any) error) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	width := len(rows[0])
+	size := max(1, dialect.MaxParams/width)
+	tuple := "(" + strings.TrimSuffix(strings.Repeat("?, ", width), ", ") + ")"
+
+	for start := 0; start < len(rows); start += size {
+		chunk := rows[start:min(start+size, len(rows))]
+		var b strings.Builder
+		b.WriteString("INSERT INTO " + into + " VALUES ")
+		args := make([]any, 0, len(chunk)*width)
+		for i, row := range chunk {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(tuple)
+			args = append(args, row...)
+		}
+		b.WriteString(suffix)
+		if err := run(dialect.Rebind(b.String()), args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package sqlstore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatches(t *testing.T) {
+	rows := [][]any{{1, "a"}, {2, "b"}, {3, "c"}}
+	dialect := Postgres
+	dialect.MaxParams = 4
+
+	var queries []string
+	var args [][]any
+	err := batches(dialect, "t (id, name)", " ON CONFLICT DO NOTHING", rows, func(query string, batch []any) error {
+		queries = append(queries, query)
+		args = append(args, batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedQueries := []string{
+		"INSERT INTO t (id, name) VALUES ($1, $2), ($3, $4) ON CONFLICT DO NOTHING",
+		"INSERT INTO t (id, name) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("Got queries %q, expected %q", queries, expectedQueries)
+	}
+	expectedArgs := [][]any{{1, "a", 2, "b"}, {3, "c"}}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("Got args %v, expected %v", args, expectedArgs)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	dialect := Postgres
+	dialect.MaxParams = 2
+
+	var queries []string
+	var args [][]any
+	err := chunks(dialect, "SELECT id FROM t WHERE key IN (%s)", []any{"a", "b", "c"}, func(query string, chunk []any) error {
+		queries = append(queries, query)
+		args = append(args, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedQueries := []string{
+		"SELECT id FROM t WHERE key IN ($1, $2)",
+		"SELECT id FROM t WHERE key IN ($1)",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("Got queries %q, expected %q", queries, expectedQueries)
+	}
+	expectedArgs := [][]any{{"a", "b"}, {"c"}}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("Got args %v, expected %v", args, expectedArgs)
+	}
+}
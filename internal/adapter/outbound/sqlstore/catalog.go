@@ -0,0 +1,277 @@
+package sqlstore
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type storedCheck struct {
+	Name string `json:"name"`
+	Score int `json:"score"`
+	Reason string `json:"reason"`
+	Documentation string `json:"documentation"`
+}
+
+func encodeChecks(checks []domain.ScorecardCheck) (any, error) {
+	if checks == nil {
+		return nil, nil
+	}
+	stored := make([]storedCheck, len(checks))
+	for i, check := range checks {
+		stored[i] = storedCheck(check)
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func decodeChecks(raw sql.NullString) ([]domain.ScorecardCheck, error) {
+	if !raw.Valid {
+		return nil, nil
+	}
+	var stored []storedCheck
+	if err := json.Unmarshal([]byte(raw.String), &stored); err != nil {
+		return nil, err
+	}
+	checks := make([]domain.ScorecardCheck, len(stored))
+	for i, check := range stored {
+		checks[i] = domain.ScorecardCheck(check)
+	}
+	return checks, nil
+}
+
+func sortByKey(rows [][]any, columns int) {
+	slices.SortFunc(rows, func(a, b []any) int {
+		for i := range columns {
+			if c := cmp.Compare(a[i].(string), b[i].(string)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+func (s *Store) saveProjects(ctx context.Context, tx *sql.Tx, nodes []domain.DependencyNode) error {
+	index := make(map[string]int)
+	var rows [][]any
+	for _, node := range nodes {
+		project := node.Project
+		if project == nil {
+			continue
+		}
+		var score, scorecardDate any
+		if project.HasScorecard() {
+			score, scorecardDate = project.Scorecard.Score, project.Scorecard.Date
+		}
+		checks, err := encodeChecks(project.Scorecard.Checks)
+		if err != nil {
+			return fmt.Errorf("Encode scorecard checks error: %w", err)
+		}
+		row := []any{
+			project.Key,
+			project.Description,
+			project.Homepage,
+			project.License,
+			project.Stars,
+			project.Forks,
+			project.OpenIssues,
+			project.OSSFuzz,
+			score,
+			scorecardDate,
+			checks,
+			project.FetchedAt,
+		}
+		if i, ok := index[project.Key]; ok {
+			rows[i] = row
+			continue
+		}
+		index[project.Key] = len(rows)
+		rows = append(rows, row)
+	}
+	sortByKey(rows, 1)
+
+	if err := Insert(ctx, tx, s.dialect,
+		`projects (project_key, description, homepage, project_license, stars, forks, open_issues, oss_fuzz, score, scorecard_date, scorecard_checks, fetched_at)`,
+		` ON CONFLICT (project_key)
+		 DO UPDATE SET description = excluded.description, homepage = excluded.homepage, project_license = excluded.project_license,
+		 	stars = excluded.stars, forks = excluded.forks, open_issues = excluded.open_issues, oss_fuzz = excluded.oss_fuzz,
+		 	score = excluded.score, scorecard_date = excluded.scorecard_date,
+		 	scorecard_checks = COALESCE(excluded.scorecard_checks, projects.scorecard_checks), fetched_at = excluded.fetched_at`,
+		rows,
+	); err != nil {
+		return fmt.Errorf("Upsert project error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) saveVersions(ctx context.Context, tx *sql.Tx, nodes []domain.DependencyNode) (map[domain.PackageRef]int64, error) {
+	index := make(map[domain.PackageRef]int)
+	var rows [][]any
+	for _, node := range nodes {
+		row := []any{
+			node.Name,
+			node.Version,
+			strings.Join(node.Advisories, ","),
+			node.Severity,
+			nullString(node.License),
+			nullString(node.ProjectKey),
+			nullString(node.LatestVersion),
+			node.Behind.Major,
+			node.Behind.Minor,
+			node.Behind.Patch,
+			node.Deprecated,
+			nullString(node.DeprecationReason),
+			node.PublishedAt,
+			node.LatestReleaseAt,
+		}
+		ref := domain.PackageRef{Name: node.Name, Version: node.Version}
+		if i, ok := index[ref]; ok {
+			rows[i] = row
+			continue
+		}
+		index[ref] = len(rows)
+		rows = append(rows, row)
+	}
+	sortByKey(rows, 2)
+
+	versionIds := make(map[domain.PackageRef]int64, len(rows))
+	if err := InsertReturning(ctx, tx, s.dialect,
+		`package_versions (name, version, advisories, severity, license, project_key, latest_version, major_behind, minor_behind, patch_behind, deprecated, deprecation_reason, published_at, latest_release_at)`,
+		` ON CONFLICT (name, version)
+		 DO UPDATE SET advisories = excluded.advisories, severity = excluded.severity, license = excluded.license, project_key = excluded.project_key,
+		 	latest_version = excluded.latest_version, major_behind = excluded.major_behind, minor_behind = excluded.minor_behind, patch_behind = excluded.patch_behind,
+		 	deprecated = excluded.deprecated, deprecation_reason = excluded.deprecation_reason, published_at = excluded.published_at, latest_release_at = excluded.latest_release_at
+		 RETURNING name, version, id`,
+		rows,
+		func(result *sql.Rows) error {
+			var ref domain.PackageRef
+			var versionId int64
+			if err := result.Scan(&ref.Name, &ref.Version, &versionId); err != nil {
+				return err
+			}
+			versionIds[ref] = versionId
+			return nil
+		},
+	); err != nil {
+		return nil, fmt.Errorf("Upsert version error: %w", err)
+	}
+	return versionIds, nil
+}
+
+func (s *Store) GetProjects(ctx context.Context, keys []string) (map[string]domain.Project, error) {
+	projects := make(map[string]domain.Project, len(keys))
+	if len(keys) == 0 {
+		return projects, nil
+	}
+
+	args := make([]any, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+	err := Select(ctx, s.db, s.dialect,
+		`SELECT project_key, description, homepage, project_license, stars, forks, open_issues, oss_fuzz, score, scorecard_date, scorecard_checks, fetched_at
+		 FROM projects
+		 WHERE project_key IN (%s)`,
+		args,
+		func(rows *sql.Rows) error {
+			var project domain.Project
+			var score sql.NullFloat64
+			var scorecardDate sql.NullTime
+			var checks sql.NullString
+			if err := rows.Scan(
+				&project.Key,
+				&project.Description,
+				&project.Homepage,
+				&project.License,
+				&project.Stars,
+				&project.Forks,
+				&project.OpenIssues,
+				&project.OSSFuzz,
+				&score,
+				&scorecardDate,
+				&checks,
+				&project.FetchedAt,
+			); err != nil {
+				return fmt.Errorf("Project scan error: %w", err)
+			}
+			var err error
+			project.Scorecard = domain.Scorecard{Score: score.Float64, Date: scorecardDate.Time}
+			if project.Scorecard.Checks, err = decodeChecks(checks); err != nil {
+				return fmt.Errorf("Decode scorecard checks error: %w", err)
+			}
+			projects[project.Key] = project
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query projects error: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *Store) saveAdvisories(ctx context.Context, tx *sql.Tx, advisories []domain.Advisory, fetchedAt time.Time) error {
+	index := make(map[string]int)
+	var rows [][]any
+	for _, advisory := range advisories {
+		row := []any{advisory.ID, advisory.Title, advisory.CVSS3Score, fetchedAt}
+		if i, ok := index[advisory.ID]; ok {
+			rows[i] = row
+			continue
+		}
+		index[advisory.ID] = len(rows)
+		rows = append(rows, row)
+	}
+	sortByKey(rows, 1)
+
+	if err := Insert(ctx, tx, s.dialect,
+		`advisories (advisory_id, title, cvss3_score, fetched_at)`,
+		` ON CONFLICT (advisory_id)
+		 DO UPDATE SET title = excluded.title, cvss3_score = excluded.cvss3_score, fetched_at = excluded.fetched_at`,
+		rows,
+	); err != nil {
+		return fmt.Errorf("Upsert advisory error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetAdvisories(ctx context.Context, ids []string) (map[string]domain.Advisory, error) {
+	advisories := make(map[string]domain.Advisory, len(ids))
+	if len(ids) == 0 {
+		return advisories, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	err := Select(ctx, s.db, s.dialect,
+		`SELECT advisory_id, title, cvss3_score
+		 FROM advisories
+		 WHERE advisory_id IN (%s)`,
+		args,
+		func(rows *sql.Rows) error {
+			var advisory domain.Advisory
+			var score sql.NullFloat64
+			if err := rows.Scan(&advisory.ID, &advisory.Title, &score); err != nil {
+				return fmt.Errorf("Advisory scan error: %w", err)
+			}
+			advisory.CVSS3Score = score.Float64
+			advisories[advisory.ID] = advisory
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query advisories error: %w", err)
+	}
+	return advisories, nil
+}
@@ -0,0 +1,37 @@
+package sqlstore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortByKey(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]any
+		columns int
+		expected [][]any
+	}{
+		{
+			name: "single key",
+			rows: [][]any{{"github.com/b", 1}, {"github.com/a", 2}, {"github.com/c", 3}},
+			columns: 1,
+			expected: [][]any{{"github.com/a", 2}, {"github.com/b", 1}, {"github.com/c", 3}},
+		},
+		{
+			name: "name and version",
+			rows: [][]any{{"qs", "6.14.0", 1}, {"express", "5.2.1", 2}, {"qs", "6.13.0", 3}},
+			columns: 2,
+			expected: [][]any{{"express", "5.2.1", 2}, {"qs", "6.13.0", 3}, {"qs", "6.14.0", 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortByKey(tt.rows, tt.columns)
+			if !reflect.DeepEqual(tt.rows, tt.expected) {
+				t.Errorf("Got %v, expected %v", tt.rows, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package sqlstore
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Dialect struct {
+	Like string
+	Numeric string
+	Boolean func(bool) any
+	Timestamp string
+	MaxParams int
+	positional bool
+}
+
+var SQLite = Dialect{
+	Like: "LIKE",
+	Numeric: "?",
+	Timestamp: "DATETIME",
+	MaxParams: 32766,
+	Boolean: func(value bool) any {
+		if value {
+			return 1
+		}
+		return 0
+	},
+}
+
+var Postgres = Dialect{
+	Like: "ILIKE",
+	Numeric: "?::float8",
+	Timestamp: "TIMESTAMPTZ",
+	MaxParams: 65535,
+	Boolean: func(value bool) any {
+		return value
+	},
+	positional: true,
+}
+
+func (d Dialect) Rebind(query string) string {
+	if !d.positional {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
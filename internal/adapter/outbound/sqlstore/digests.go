@@ -0,0 +1,33 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (s *Store) LastDigestSent(ctx context.Context) (*time.Time, error) {
+	var sentAt time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT sent_at FROM digests WHERE id = 1`).Scan(&sentAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Query digest error: %w", err)
+	}
+	sentAt = sentAt.UTC()
+	return &sentAt, nil
+}
+
+func (s *Store) SaveDigestSent(ctx context.Context, sentAt time.Time) error {
+	if _, err := s.db.ExecContext(ctx,
+		s.rebind(`INSERT INTO digests (id, sent_at) VALUES (1, ?)
+		 ON CONFLICT (id) DO UPDATE SET sent_at = excluded.sent_at`),
+		sentAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("Save digest error: %w", err)
+	}
+	return nil
+}
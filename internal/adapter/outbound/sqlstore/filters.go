@@ -0,0 +1,198 @@
+package sqlstore
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type columnKind int
+
+const (
+	textColumn columnKind = iota
+	numericColumn
+	booleanColumn
+)
+
+type column struct {
+	name string
+	kind columnKind
+}
+
+var allowedColumns = map[string]column {
+	"name": {"name", textColumn},
+	"version": {"version", textColumn},
+	"relation": {"relation", textColumn},
+	"license": {"license", textColumn},
+	"score": {"score", numericColumn},
+	"severity": {"severity", numericColumn},
+	"depth": {"depth", numericColumn},
+	"latest_version": {"latest_version", textColumn},
+	"major_behind": {"major_behind", numericColumn},
+	"minor_behind": {"minor_behind", numericColumn},
+	"patch_behind": {"patch_behind", numericColumn},
+	"deprecated": {"deprecated", booleanColumn},
+	"status": {"status", textColumn},
+}
+
+var comparisonOperators = map[domain.Operator]string {
+	domain.FilterEq: "=",
+	domain.FilterNe: "!=",
+	domain.FilterLt: "<",
+	domain.FilterLte: "<=",
+	domain.FilterGt: ">",
+	domain.FilterGte: ">=",
+}
+
+const (
+	maxFilterConditions = 64
+	maxFilterDepth = 8
+)
+
+type filterBuilder struct {
+	dialect Dialect
+	args []any
+	conditions int
+}
+
+func BuildFilters(filters []domain.Filter, dialect Dialect) (string, []any, error) {
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+
+	b := &filterBuilder{dialect: dialect}
+	clauses := make([]string, 0, len(filters))
+	for _, f := range filters {
+		clause, err := b.build(f, 0)
+		if err != nil {
+			return "", nil, domain.NewError(domain.KindInvalidInput, err)
+		}
+		clauses = append(clauses, clause)
+	}
+	return " AND " + strings.Join(clauses, " AND "), b.args, nil
+}
+
+func (b *filterBuilder) build(f domain.Filter, depth int) (string, error) {
+	if !f.IsGroup() {
+		b.conditions++
+		if b.conditions > maxFilterConditions {
+			return "", fmt.Errorf("Too many filter conditions, maximum is %d", maxFilterConditions)
+		}
+		return b.condition(f)
+	}
+
+	if depth >= maxFilterDepth {
+		return "", fmt.Errorf("Filter nesting too deep, maximum is %d", maxFilterDepth)
+	}
+	joiner := " AND "
+	switch f.Logic {
+	case domain.LogicAnd, "":
+	case domain.LogicOr:
+		joiner = " OR "
+	default:
+		return "", fmt.Errorf("Unknown logic error: %q", f.Logic)
+	}
+
+	clauses := make([]string, 0, len(f.Filters))
+	for _, child := range f.Filters {
+		clause, err := b.build(child, depth+1)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, clause)
+	}
+	return "(" + strings.Join(clauses, joiner) + ")", nil
+}
+
+func (b *filterBuilder) condition(f domain.Filter) (string, error) {
+	col, ok := allowedColumns[f.Column]
+	if !ok {
+		return "", fmt.Errorf("Unknown filter error: %q", f.Column)
+	}
+
+	if op, ok := comparisonOperators[f.Operator]; ok {
+		if col.kind != numericColumn && f.Operator != domain.FilterEq && f.Operator != domain.FilterNe {
+			return "", fmt.Errorf("Operator %q is not supported for column %q", f.Operator, f.Column)
+		}
+		val, err := b.value(col, f.Value)
+		if err != nil {
+			return "", err
+		}
+		b.args = append(b.args, val)
+		return fmt.Sprintf("%s %s %s", col.name, op, b.placeholder(col)), nil
+	}
+
+	switch f.Operator {
+	case domain.FilterIn:
+		values := strings.Split(f.Value, ",")
+		if len(values) > maxFilterConditions {
+			return "", fmt.Errorf("Too many values in filter, maximum is %d", maxFilterConditions)
+		}
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			val, err := b.value(col, strings.TrimSpace(v))
+			if err != nil {
+				return "", err
+			}
+			placeholders[i] = b.placeholder(col)
+			b.args = append(b.args, val)
+		}
+		return fmt.Sprintf("%s IN (%s)", col.name, strings.Join(placeholders, ", ")), nil
+	case domain.FilterPrefix, domain.FilterLike:
+		if col.kind != textColumn {
+			return "", fmt.Errorf("Operator %q is not supported for column %q", f.Operator, f.Column)
+		}
+		if f.Operator == domain.FilterPrefix {
+			b.args = append(b.args, escapeLike(f.Value)+"%")
+		} else {
+			b.args = append(b.args, f.Value)
+		}
+		return fmt.Sprintf(`%s %s ? ESCAPE '\'`, col.name, b.dialect.Like), nil
+	case domain.FilterIsNull:
+		switch f.Value {
+		case "", "true":
+			return fmt.Sprintf("%s IS NULL", col.name), nil
+		case "false":
+			return fmt.Sprintf("%s IS NOT NULL", col.name), nil
+		default:
+			return "", fmt.Errorf("Null filter value must be true or false: %q", f.Value)
+		}
+	default:
+		return "", fmt.Errorf("Unknown operator error: %q", f.Operator)
+	}
+}
+
+func (b *filterBuilder) placeholder(col column) string {
+	if col.kind == numericColumn {
+		return b.dialect.Numeric
+	}
+	return "?"
+}
+
+func (b *filterBuilder) value(col column, value string) (any, error) {
+	switch col.kind {
+	case textColumn:
+		return value, nil
+	case booleanColumn:
+		switch value {
+		case "true":
+			return b.dialect.Boolean(true), nil
+		case "false":
+			return b.dialect.Boolean(false), nil
+		default:
+			return nil, fmt.Errorf("Filter value must be true or false: %q", value)
+		}
+	}
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+		return nil, fmt.Errorf("Filter value must be numeric: %q", value)
+	}
+	return val, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
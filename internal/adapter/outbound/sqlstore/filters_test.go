@@ -0,0 +1,148 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestBuildFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		filters []domain.Filter
+		expectedClause string
+		expectedArgs []any
+		Error string
+	}{
+		{
+			name: "eq operator",
+			filters: []domain.Filter{
+				{Column: "name", Operator: domain.FilterEq, Value: "express"},
+			},
+			expectedClause: " AND name = ?",
+			expectedArgs: []any{"express"},
+		},
+		{
+			name: "gt operator",
+			filters: []domain.Filter{
+				{Column: "score", Operator: domain.FilterGte, Value: "5.5"},
+			},
+			expectedClause: " AND score >= ?",
+			expectedArgs: []any{5.5},
+		},
+		{
+			name: "injection",
+			filters: []domain.Filter{
+				{Column: "injected", Operator: domain.FilterEq, Value: "x"},
+			},
+			Error: "Unknown filter error: \"injected\"",
+		},
+		{
+			name: "boolean column",
+			filters: []domain.Filter{
+				{Column: "deprecated", Operator: domain.FilterEq, Value: "true"},
+			},
+			expectedClause: " AND deprecated = ?",
+			expectedArgs: []any{1},
+		},
+		{
+			name: "boolean column rejects other values",
+			filters: []domain.Filter{
+				{Column: "deprecated", Operator: domain.FilterEq, Value: "1"},
+			},
+			Error: "Filter value must be true or false: \"1\"",
+		},
+		{
+			name: "in operator",
+			filters: []domain.Filter{
+				{Column: "relation", Operator: domain.FilterIn, Value: "DIRECT,SELF"},
+			},
+			expectedClause: " AND relation IN (?, ?)",
+			expectedArgs: []any{"DIRECT", "SELF"},
+		},
+		{
+			name: "prefix operator escapes wildcards",
+			filters: []domain.Filter{
+				{Column: "version", Operator: domain.FilterPrefix, Value: "1_0%"},
+			},
+			expectedClause: " AND version LIKE ? ESCAPE '\\'",
+			expectedArgs: []any{"1\\_0\\%%"},
+		},
+		{
+			name: "isnull operator",
+			filters: []domain.Filter{
+				{Column: "license", Operator: domain.FilterIsNull},
+				{Column: "score", Operator: domain.FilterIsNull, Value: "false"},
+			},
+			expectedClause: " AND license IS NULL AND score IS NOT NULL",
+		},
+		{
+			name: "or group",
+			filters: []domain.Filter{
+				{Column: "depth", Operator: domain.FilterLte, Value: "2"},
+				{Logic: domain.LogicOr, Filters: []domain.Filter{
+					{Column: "score", Operator: domain.FilterLt, Value: "4"},
+					{Column: "severity", Operator: domain.FilterGt, Value: "7"},
+				}},
+			},
+			expectedClause: " AND depth <= ? AND (score < ? OR severity > ?)",
+			expectedArgs: []any{2.0, 4.0, 7.0},
+		},
+		{
+			name: "ordering on text column",
+			filters: []domain.Filter{
+				{Column: "version", Operator: domain.FilterGt, Value: "1.0.0"},
+			},
+			Error: "Operator \"gt\" is not supported for column \"version\"",
+		},
+		{
+			name: "non numeric value",
+			filters: []domain.Filter{
+				{Column: "score", Operator: domain.FilterNe, Value: "1 OR 1=1"},
+			},
+			Error: "Filter value must be numeric: \"1 OR 1=1\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, args, err := BuildFilters(tt.filters, SQLite)
+			if err != nil && err.Error() != tt.Error {
+				t.Fatalf("Got error %s, expected %s", err.Error(), tt.Error)
+			}
+			if err == nil && tt.Error != "" {
+				t.Fatalf("Got no error, expected %s", tt.Error)
+			}
+
+			if clause != tt.expectedClause {
+				t.Errorf("Got clause %s, expected %s", clause, tt.expectedClause)
+			}
+			if len(args) != len(tt.expectedArgs) {
+				t.Fatalf("Got args len %d, expected %d", len(args), len(tt.expectedArgs))
+			}
+			for i, arg := range args {
+				if arg != tt.expectedArgs[i] {
+					t.Errorf("Got args[%d] %v, expected %v", i, arg, tt.expectedArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresDialect(t *testing.T) {
+	clause, args, err := BuildFilters([]domain.Filter{
+		{Column: "depth", Operator: domain.FilterLte, Value: "2"},
+		{Column: "name", Operator: domain.FilterLike, Value: "express%"},
+		{Column: "deprecated", Operator: domain.FilterEq, Value: "false"},
+	}, Postgres)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+	expected := " AND depth <= $1::float8 AND name ILIKE $2 ESCAPE '\\' AND deprecated = $3"
+	if query := Postgres.Rebind(clause); query != expected {
+		t.Errorf("Got clause %s, expected %s", query, expected)
+	}
+	if len(args) != 3 || args[0] != 2.0 || args[1] != "express%" || args[2] != false {
+		t.Errorf("Got args %v, expected [2 express%% false]", args)
+	}
+}
@@ -0,0 +1,76 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (s *Store) ListEdges(ctx context.Context, name string) ([]domain.Edge, error) {
+	rows, err := s.db.QueryContext(ctx,
+		s.rebind(`SELECT f.name, f.version, t.name, t.version
+		 FROM dependency_edges e
+		 JOIN packages p ON p.id = e.package_id
+		 JOIN package_versions f ON f.id = e.from_version_id
+		 JOIN package_versions t ON t.id = e.to_version_id
+		 WHERE p.name = ?
+		 ORDER BY e.id`),
+		name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query edges error: %w", err)
+	}
+	defer rows.Close()
+
+	var edges []domain.Edge
+	for rows.Next() {
+		var edge domain.Edge
+		if err := rows.Scan(&edge.From.Name, &edge.From.Version, &edge.To.Name, &edge.To.Version); err != nil {
+			return nil, fmt.Errorf("Edge scan error: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Edge iteration error: %w", err)
+	}
+	return edges, nil
+}
+
+func (s *Store) FindDependents(ctx context.Context, dependency string, version string) ([]domain.Dependent, error) {
+	query := `SELECT p.name, p.version, n.version, n.relation, n.depth
+		 FROM graph_nodes n
+		 JOIN packages p ON p.id = n.package_id
+		 WHERE n.name = ?`
+	args := []any{dependency}
+	if version != "" {
+		query += ` AND n.version = ?`
+		args = append(args, version)
+	}
+	query += ` ORDER BY p.name, n.version`
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("Query dependents error: %w", err)
+	}
+	defer rows.Close()
+
+	var dependents []domain.Dependent
+	for rows.Next() {
+		var dependent domain.Dependent
+		if err := rows.Scan(
+			&dependent.Package.Name,
+			&dependent.Package.Version,
+			&dependent.Version,
+			&dependent.Relation,
+			&dependent.Depth,
+		); err != nil {
+			return nil, fmt.Errorf("Dependent scan error: %w", err)
+		}
+		dependents = append(dependents, dependent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Dependent iteration error: %w", err)
+	}
+	return dependents, nil
+}
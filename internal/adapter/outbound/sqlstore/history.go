@@ -0,0 +1,170 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (s *Store) saveSnapshot(ctx context.Context, tx *sql.Tx, pkg *domain.Package, changes []domain.Change) error {
+	var snapshotId int64
+	if err := tx.QueryRowContext(ctx,
+		s.rebind(`INSERT INTO snapshots (package_name, version, taken_at, health)
+		 VALUES (?, ?, ?, ?)
+		 RETURNING id`),
+		pkg.PackageRef.Name,
+		pkg.PackageRef.Version,
+		pkg.LastUpdatedAt,
+		pkg.Health,
+	).Scan(&snapshotId); err != nil {
+		return fmt.Errorf("Insert snapshot error: %w", err)
+	}
+
+	nodes := make([][]any, 0, len(pkg.Dependencies))
+	for _, node := range pkg.Dependencies {
+		nodes = append(nodes, []any{
+			snapshotId,
+			node.Name,
+			node.Version,
+			node.Relation,
+			node.Score,
+			strings.Join(node.Advisories, ","),
+			node.Severity,
+			nullString(node.License),
+			node.Depth,
+			node.FanIn,
+		})
+	}
+	if err := Insert(ctx, tx, s.dialect,
+		`snapshot_nodes (snapshot_id, name, version, relation, score, advisories, severity, license, depth, fan_in)`, ``, nodes,
+	); err != nil {
+		return fmt.Errorf("Insert snapshot node error: %w", err)
+	}
+
+	rows := make([][]any, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, []any{snapshotId, change.Kind, change.Dependency, change.From, change.To})
+	}
+	if err := Insert(ctx, tx, s.dialect,
+		`snapshot_changes (snapshot_id, kind, dependency, from_value, to_value)`, ``, rows,
+	); err != nil {
+		return fmt.Errorf("Insert snapshot change error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListSnapshots(ctx context.Context, name string, limit int) ([]domain.Snapshot, error) {
+	query := `SELECT id, package_name, version, taken_at, health FROM snapshots`
+	var args []any
+	if name != "" {
+		query += ` WHERE package_name = ?`
+		args = append(args, name)
+	}
+	query += ` ORDER BY taken_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("Query snapshots error: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []domain.Snapshot
+	index := make(map[int64]int)
+	for rows.Next() {
+		var snapshot domain.Snapshot
+		if err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.PackageRef.Name,
+			&snapshot.PackageRef.Version,
+			&snapshot.TakenAt,
+			&snapshot.Health,
+		); err != nil {
+			return nil, fmt.Errorf("Snapshot scan error: %w", err)
+		}
+		index[snapshot.ID] = len(snapshots)
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Snapshot iteration error: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]any, len(snapshots))
+	for i, snapshot := range snapshots {
+		ids[i] = snapshot.ID
+	}
+	err = Select(ctx, s.db, s.dialect,
+		`SELECT snapshot_id, kind, dependency, from_value, to_value
+		 FROM snapshot_changes
+		 WHERE snapshot_id IN (%s)
+		 ORDER BY id`,
+		ids,
+		func(changeRows *sql.Rows) error {
+			var snapshotId int64
+			var change domain.Change
+			if err := changeRows.Scan(&snapshotId, &change.Kind, &change.Dependency, &change.From, &change.To); err != nil {
+				return fmt.Errorf("Snapshot change scan error: %w", err)
+			}
+			i := index[snapshotId]
+			snapshots[i].Changes = append(snapshots[i].Changes, change)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query snapshot changes error: %w", err)
+	}
+	return snapshots, nil
+}
+
+func (s *Store) PruneSnapshots(ctx context.Context, name string, keep int) error {
+	if _, err := s.db.ExecContext(ctx,
+		s.rebind(`DELETE FROM snapshots
+		 WHERE package_name = ? AND id NOT IN (
+		 	SELECT id FROM snapshots
+		 	WHERE package_name = ?
+		 	ORDER BY taken_at DESC, id DESC
+		 	LIMIT ?
+		 )`),
+		name,
+		name,
+		keep,
+	); err != nil {
+		return fmt.Errorf("Prune snapshots error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListScoreHistory(ctx context.Context, name string, dependency string) ([]domain.ScorePoint, error) {
+	rows, err := s.db.QueryContext(ctx,
+		s.rebind(`SELECT s.taken_at, n.version, n.score
+		 FROM snapshot_nodes n
+		 JOIN snapshots s ON s.id = n.snapshot_id
+		 WHERE s.package_name = ? AND n.name = ?
+		 ORDER BY s.taken_at, s.id, n.version`),
+		name,
+		dependency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query score history error: %w", err)
+	}
+	defer rows.Close()
+
+	var points []domain.ScorePoint
+	for rows.Next() {
+		var point domain.ScorePoint
+		if err := rows.Scan(&point.TakenAt, &point.Version, &point.Score); err != nil {
+			return nil, fmt.Errorf("Score history scan error: %w", err)
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Score history iteration error: %w", err)
+	}
+	return points, nil
+}
@@ -0,0 +1,267 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrSchemaTooNew = errors.New("Database schema is newer than this build supports")
+
+type Migration struct {
+	Version int
+	Name string
+	AppliedAt *time.Time
+	up string
+	down string
+}
+
+type Migrator struct {
+	db *sql.DB
+	dialect Dialect
+	migrations []Migration
+	adopt func(ctx context.Context, initial string) error
+	lock func(ctx context.Context) (func(), error)
+}
+
+func NewMigrator(
+	db *sql.DB,
+	files fs.FS,
+	dialect Dialect,
+	adopt func(ctx context.Context, initial string) error,
+	lock func(ctx context.Context) (func(), error),
+) (*Migrator, error) {
+	migrations, err := loadMigrations(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations, adopt: adopt, lock: lock}, nil
+}
+
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(files, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("List migrations error: %w", err)
+	}
+	byVersion := make(map[int]*Migration)
+	for _, path := range paths {
+		file := strings.TrimPrefix(path, "migrations/")
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("Invalid migration file name: %s", file)
+		}
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("Invalid migration version: %s", file)
+		}
+		content, err := fs.ReadFile(files, path)
+		if err != nil {
+			return nil, fmt.Errorf("Read migration %s error: %w", file, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("Conflicting migration names for version %d: %s, %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("Migration %d_%s needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("Missing migration version %d", i+1)
+		}
+	}
+	return migrations, nil
+}
+
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	var version int
+	if err := m.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`,
+	).Scan(&version); err != nil {
+		return 0, fmt.Errorf("Schema version error: %w", err)
+	}
+	return version, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("Query migrations error: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("Migration scan error: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Migration iteration error: %w", err)
+	}
+
+	status := make([]Migration, len(m.migrations))
+	for i, migration := range m.migrations {
+		if appliedAt, ok := applied[migration.Version]; ok {
+			migration.AppliedAt = &appliedAt
+		}
+		status[i] = migration
+	}
+	return status, nil
+}
+
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if version > m.Latest() {
+		return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaTooNew, version, m.Latest())
+	}
+	return nil
+}
+
+func (m *Migrator) acquire(ctx context.Context) (func(), error) {
+	if m.lock == nil {
+		return func() {}, nil
+	}
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Migration lock error: %w", err)
+	}
+	return unlock, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	unlock, err := m.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.Check(ctx); err != nil {
+		return 0, err
+	}
+	version, err := m.Version(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if version == 0 && m.adopt != nil {
+		if err := m.adopt(ctx, m.migrations[0].up); err != nil {
+			return 0, fmt.Errorf("Adopting existing schema: %w", err)
+		}
+	}
+
+	applied := 0
+	for _, migration := range m.migrations[version:] {
+		if err := m.apply(ctx, migration, migration.up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx,
+				m.dialect.Rebind(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`),
+				migration.Version,
+				migration.Name,
+				time.Now().UTC(),
+			)
+			return err
+		}); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	unlock, err := m.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.Check(ctx); err != nil {
+		return 0, err
+	}
+	version, err := m.Version(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+	for ; reverted < steps && version > 0; version-- {
+		migration := m.migrations[version-1]
+		if err := m.apply(ctx, migration, migration.down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.dialect.Rebind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version)
+			return err
+		}); err != nil {
+			return reverted, err
+		}
+		reverted++
+	}
+	return reverted, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration, statements string, record func(*sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Begin Transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return fmt.Errorf("Migration %d_%s error: %w", migration.Version, migration.Name, err)
+	}
+	if err := record(tx); err != nil {
+		return fmt.Errorf("Record migration %d_%s error: %w", migration.Version, migration.Name, err)
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version		INTEGER PRIMARY KEY,
+			name		TEXT NOT NULL,
+			applied_at	`+m.dialect.Timestamp+` NOT NULL
+		)`,
+	); err != nil {
+		return fmt.Errorf("Create schema_migrations error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package sqlstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+var sortColumns = map[string]string {
+	"": "id",
+	"name": "name",
+	"score": "COALESCE(score, -1)",
+	"depth": "depth",
+}
+
+type Order struct {
+	Key string
+	desc bool
+	spec string
+}
+
+type cursor struct {
+	Sort string `json:"s"`
+	Key any `json:"k"`
+	ID int64 `json:"i"`
+}
+
+func BuildOrder(sort domain.Sort) (Order, error) {
+	key, ok := sortColumns[sort.Column]
+	if !ok {
+		return Order{}, domain.Errorf(domain.KindInvalidInput, "Unknown sort error: %q", sort.Column)
+	}
+	spec := sort.Column
+	if sort.Desc {
+		spec = "-" + spec
+	}
+	return Order{Key: key, desc: sort.Desc, spec: spec}, nil
+}
+
+func (o Order) Clause() string {
+	if o.desc {
+		return fmt.Sprintf(" ORDER BY %s DESC, id ASC", o.Key)
+	}
+	return fmt.Sprintf(" ORDER BY %s ASC, id ASC", o.Key)
+}
+
+func (o Order) After(encoded string, dialect Dialect) (string, []any, error) {
+	if encoded == "" {
+		return "", nil, nil
+	}
+	key, id, err := o.Decode(encoded)
+	if err != nil {
+		return "", nil, err
+	}
+
+	op := ">"
+	if o.desc {
+		op = "<"
+	}
+	placeholder := dialect.Numeric
+	if _, isText := key.(string); isText {
+		placeholder = "?"
+	}
+	clause := fmt.Sprintf(" AND (%s %s %s OR (%s = %s AND id > ?))", o.Key, op, placeholder, o.Key, placeholder)
+	return clause, []any{key, key, id}, nil
+}
+
+func (o Order) Decode(encoded string) (any, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, 0, domain.Errorf(domain.KindInvalidInput, "Invalid cursor error")
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != o.spec {
+		return nil, 0, domain.Errorf(domain.KindInvalidInput, "Invalid cursor error")
+	}
+	_, isText := c.Key.(string)
+	_, isNumber := c.Key.(float64)
+	if (o.Key == "name" && !isText) || (o.Key != "name" && !isNumber) {
+		return nil, 0, domain.Errorf(domain.KindInvalidInput, "Invalid cursor error")
+	}
+	return c.Key, c.ID, nil
+}
+
+func (o Order) Cursor(key any, id int64) string {
+	raw, _ := json.Marshal(cursor{Sort: o.spec, Key: key, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,152 @@
+package sqlstore
+
+import (
+	"encoding/base64"
+	"slices"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestBuildOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		sort domain.Sort
+		expectedClause string
+		Error string
+	}{
+		{name: "default", expectedClause: " ORDER BY id ASC, id ASC"},
+		{name: "name ascending", sort: domain.Sort{Column: "name"}, expectedClause: " ORDER BY name ASC, id ASC"},
+		{name: "score descending", sort: domain.Sort{Column: "score", Desc: true}, expectedClause: " ORDER BY COALESCE(score, -1) DESC, id ASC"},
+		{name: "depth descending", sort: domain.Sort{Column: "depth", Desc: true}, expectedClause: " ORDER BY depth DESC, id ASC"},
+		{name: "unknown column", sort: domain.Sort{Column: "secret"}, Error: "Unknown sort error: \"secret\""},
+		{name: "injection", sort: domain.Sort{Column: "name; DROP TABLE packages"}, Error: "Unknown sort error: \"name; DROP TABLE packages\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := BuildOrder(tt.sort)
+			if tt.Error != "" {
+				if err == nil || err.Error() != tt.Error || domain.KindOf(err) != domain.KindInvalidInput {
+					t.Fatalf("Got error %v, expected invalid input %q", err, tt.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			if clause := order.Clause(); clause != tt.expectedClause {
+				t.Errorf("Got clause %s, expected %s", clause, tt.expectedClause)
+			}
+		})
+	}
+}
+
+func TestOrderAfter(t *testing.T) {
+	byName, _ := BuildOrder(domain.Sort{Column: "name"})
+	byScore, _ := BuildOrder(domain.Sort{Column: "score", Desc: true})
+	byDepth, _ := BuildOrder(domain.Sort{Column: "depth"})
+
+	tests := []struct {
+		name string
+		order Order
+		cursor string
+		dialect Dialect
+		expectedClause string
+		expectedArgs []any
+	}{
+		{name: "first page", order: byName, dialect: SQLite},
+		{
+			name: "name ascending",
+			order: byName,
+			cursor: byName.Cursor("express", 3),
+			dialect: SQLite,
+			expectedClause: " AND (name > ? OR (name = ? AND id > ?))",
+			expectedArgs: []any{"express", "express", int64(3)},
+		},
+		{
+			name: "score descending",
+			order: byScore,
+			cursor: byScore.Cursor(7.5, 2),
+			dialect: SQLite,
+			expectedClause: " AND (COALESCE(score, -1) < ? OR (COALESCE(score, -1) = ? AND id > ?))",
+			expectedArgs: []any{7.5, 7.5, int64(2)},
+		},
+		{
+			name: "postgres numeric",
+			order: byDepth,
+			cursor: byDepth.Cursor(2, 9),
+			dialect: Postgres,
+			expectedClause: " AND (depth > $1::float8 OR (depth = $2::float8 AND id > $3))",
+			expectedArgs: []any{2.0, 2.0, int64(9)},
+		},
+		{
+			name: "postgres text",
+			order: byName,
+			cursor: byName.Cursor("qs", 4),
+			dialect: Postgres,
+			expectedClause: " AND (name > $1 OR (name = $2 AND id > $3))",
+			expectedArgs: []any{"qs", "qs", int64(4)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, args, err := tt.order.After(tt.cursor, tt.dialect)
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			if clause = tt.dialect.Rebind(clause); clause != tt.expectedClause {
+				t.Errorf("Got clause %s, expected %s", clause, tt.expectedClause)
+			}
+			if !slices.Equal(args, tt.expectedArgs) {
+				t.Errorf("Got args %v, expected %v", args, tt.expectedArgs)
+			}
+		})
+	}
+}
+
+func TestOrderDecode(t *testing.T) {
+	byName, _ := BuildOrder(domain.Sort{Column: "name"})
+	byNameDesc, _ := BuildOrder(domain.Sort{Column: "name", Desc: true})
+	byScore, _ := BuildOrder(domain.Sort{Column: "score"})
+	byID, _ := BuildOrder(domain.Sort{})
+
+	tests := []struct {
+		name string
+		order Order
+		cursor string
+		expectedKey any
+		expectedID int64
+		Error bool
+	}{
+		{name: "text round trip", order: byName, cursor: byName.Cursor("under_score", 5), expectedKey: "under_score", expectedID: 5},
+		{name: "number round trip", order: byScore, cursor: byScore.Cursor(4.2, 7), expectedKey: 4.2, expectedID: 7},
+		{name: "missing score round trip", order: byScore, cursor: byScore.Cursor(-1, 8), expectedKey: -1.0, expectedID: 8},
+		{name: "id round trip", order: byID, cursor: byID.Cursor(12, 12), expectedKey: 12.0, expectedID: 12},
+		{name: "not base64", order: byName, cursor: "garbage!", Error: true},
+		{name: "not json", order: byName, cursor: base64.RawURLEncoding.EncodeToString([]byte("garbage")), Error: true},
+		{name: "other column", order: byName, cursor: byScore.Cursor(4.2, 7), Error: true},
+		{name: "other direction", order: byName, cursor: byNameDesc.Cursor("qs", 7), Error: true},
+		{name: "number key for text column", order: byName, cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"name","k":1,"i":1}`)), Error: true},
+		{name: "text key for number column", order: byScore, cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"score","k":"1 OR 1=1","i":1}`)), Error: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, id, err := tt.order.Decode(tt.cursor)
+			if tt.Error {
+				if err == nil || domain.KindOf(err) != domain.KindInvalidInput {
+					t.Fatalf("Got error %v, expected invalid cursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error %v", err)
+			}
+			if key != tt.expectedKey || id != tt.expectedID {
+				t.Errorf("Got %v/%d, expected %v/%d", key, id, tt.expectedKey, tt.expectedID)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type Store struct {
+	db *sql.DB
+	dialect Dialect
+}
+
+func NewStore(db *sql.DB, dialect Dialect) *Store {
+	return &Store{db: db, dialect: dialect}
+}
+
+func (s *Store) rebind(query string) string {
+	return s.dialect.Rebind(query)
+}
+
+func (s *Store) Save(ctx context.Context, pkg *domain.Package, changes []domain.Change) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Begin Transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	var packageId int64
+	if err := tx.QueryRowContext(ctx,
+		s.rebind(`INSERT INTO packages (name, version, last_updated_at, health)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT (name)
+		 DO UPDATE SET version = excluded.version, last_updated_at = excluded.last_updated_at, health = excluded.health
+		 RETURNING id`),
+		 pkg.PackageRef.Name,
+		 pkg.PackageRef.Version,
+		 pkg.LastUpdatedAt,
+		 pkg.Health,
+	).Scan(&packageId); err != nil {
+		return fmt.Errorf("Upsert error: %w", err)
+	}
+	pkg.ID = packageId
+
+	if _, err = tx.ExecContext(ctx,
+		s.rebind(`DELETE FROM dependency_nodes WHERE package_id = ?`), packageId,
+	); err != nil {
+		return fmt.Errorf("Delete old nodes error: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		s.rebind(`DELETE FROM dependency_edges WHERE package_id = ?`), packageId,
+	); err != nil {
+		return fmt.Errorf("Delete old edges error: %w", err)
+	}
+
+	if err := s.saveProjects(ctx, tx, pkg.Dependencies); err != nil {
+		return err
+	}
+	if err := s.saveAdvisories(ctx, tx, pkg.Advisories, pkg.LastUpdatedAt); err != nil {
+		return err
+	}
+	versionIds, err := s.saveVersions(ctx, tx, pkg.Dependencies)
+	if err != nil {
+		return err
+	}
+
+	nodes := make([][]any, 0, len(pkg.Dependencies))
+	for _, node := range pkg.Dependencies {
+		nodes = append(nodes, []any{
+			packageId,
+			versionIds[domain.PackageRef{Name: node.Name, Version: node.Version}],
+			node.Relation,
+			node.Depth,
+			node.FanIn,
+			string(node.Status),
+			node.StatusMessage,
+		})
+	}
+	if err := Insert(ctx, tx, s.dialect,
+		`dependency_nodes (package_id, version_id, relation, depth, fan_in, status, status_message)`, ``, nodes,
+	); err != nil {
+		return fmt.Errorf("Insert node error: %w", err)
+	}
+
+	edges := make([][]any, 0, len(pkg.Edges))
+	for _, edge := range pkg.Edges {
+		from, ok := versionIds[edge.From]
+		to, ok2 := versionIds[edge.To]
+		if !ok || !ok2 {
+			continue
+		}
+		edges = append(edges, []any{packageId, from, to})
+	}
+	if err := Insert(ctx, tx, s.dialect,
+		`dependency_edges (package_id, from_version_id, to_version_id)`, ``, edges,
+	); err != nil {
+		return fmt.Errorf("Insert edge error: %w", err)
+	}
+
+	if err := s.saveSnapshot(ctx, tx, pkg, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) scanPackageWithDeps(ctx context.Context, row *sql.Row, query domain.NodeQuery) (*domain.Package, error) {
+	pkg := &domain.Package{}
+
+	err := row.Scan(
+		&pkg.ID,
+		&pkg.PackageRef.Name,
+		&pkg.PackageRef.Version,
+		&pkg.LastUpdatedAt,
+		&pkg.Health,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Query error: %w", err)
+	}
+
+	filterClause, filterArgs, err := BuildFilters(query.Filters, s.dialect)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid filter error: %w", err)
+	}
+	order, err := BuildOrder(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cursorClause, cursorArgs, err := order.After(query.Cursor, s.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Page = &domain.Page{Limit: query.Limit}
+	if err := s.db.QueryRowContext(ctx,
+		s.rebind(`SELECT COUNT(*) FROM graph_nodes WHERE package_id = ?`+filterClause),
+		append([]any{pkg.ID}, filterArgs...)...,
+	).Scan(&pkg.Page.Total); err != nil {
+		return nil, fmt.Errorf("Count nodes error: %w", err)
+	}
+
+	nodesQuery := `SELECT id, ` + order.Key + `, name, version, relation, score, advisories, severity, license, depth, fan_in, project_key,
+		 	latest_version, major_behind, minor_behind, patch_behind, deprecated, deprecation_reason, published_at, latest_release_at,
+		 	description, homepage, project_license, stars, forks, open_issues, oss_fuzz, scorecard_date, project_fetched_at,
+		 	status, status_message
+		 FROM graph_nodes
+		 WHERE package_id = ?` + filterClause + cursorClause + order.Clause()
+	nodesArgs := append([]any{pkg.ID}, filterArgs...)
+	nodesArgs = append(nodesArgs, cursorArgs...)
+	if query.Limit > 0 {
+		nodesQuery += ` LIMIT ?`
+		nodesArgs = append(nodesArgs, query.Limit+1)
+	}
+	rows, err := s.db.QueryContext(ctx, s.rebind(nodesQuery), nodesArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("Query nodes error: %w", err)
+	}
+	defer rows.Close()
+
+	var lastId int64
+	var lastKey any
+	for rows.Next() {
+		if query.Limit > 0 && len(pkg.Dependencies) == query.Limit {
+			pkg.Page.NextCursor = order.Cursor(lastKey, lastId)
+			break
+		}
+		var node domain.DependencyNode
+		var advisories string
+		var license, projectKey, latestVersion, deprecationReason sql.NullString
+		var publishedAt, latestReleaseAt sql.NullTime
+		var description, homepage, projectLicense sql.NullString
+		var stars, forks, openIssues sql.NullInt64
+		var ossFuzz sql.NullBool
+		var scorecardDate, fetchedAt sql.NullTime
+		if err := rows.Scan(
+			&lastId,
+			&lastKey,
+			&node.Name,
+			&node.Version,
+			&node.Relation,
+			&node.Score,
+			&advisories,
+			&node.Severity,
+			&license,
+			&node.Depth,
+			&node.FanIn,
+			&projectKey,
+			&latestVersion,
+			&node.Behind.Major,
+			&node.Behind.Minor,
+			&node.Behind.Patch,
+			&node.Deprecated,
+			&deprecationReason,
+			&publishedAt,
+			&latestReleaseAt,
+			&description,
+			&homepage,
+			&projectLicense,
+			&stars,
+			&forks,
+			&openIssues,
+			&ossFuzz,
+			&scorecardDate,
+			&fetchedAt,
+			&node.Status,
+			&node.StatusMessage,
+		); err != nil {
+			return nil, fmt.Errorf("Node scan error: %w", err)
+		}
+		if advisories != "" {
+			node.Advisories = strings.Split(advisories, ",")
+		}
+		node.License = license.String
+		node.ProjectKey = projectKey.String
+		node.LatestVersion = latestVersion.String
+		node.DeprecationReason = deprecationReason.String
+		if publishedAt.Valid {
+			node.PublishedAt = &publishedAt.Time
+		}
+		if latestReleaseAt.Valid {
+			node.LatestReleaseAt = &latestReleaseAt.Time
+		}
+		if fetchedAt.Valid {
+			node.Project = &domain.Project{
+				Key: node.ProjectKey,
+				Description: description.String,
+				Homepage: homepage.String,
+				License: projectLicense.String,
+				Stars: int(stars.Int64),
+				Forks: int(forks.Int64),
+				OpenIssues: int(openIssues.Int64),
+				OSSFuzz: ossFuzz.Bool,
+				FetchedAt: fetchedAt.Time,
+			}
+			if node.Score != nil {
+				node.Project.Scorecard = domain.Scorecard{Score: *node.Score, Date: scorecardDate.Time}
+			}
+		}
+		pkg.Dependencies = append(pkg.Dependencies, node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Node iteration error: %w", err)
+	}
+
+	return pkg, nil
+}
+
+func (s *Store) GetCurrent(ctx context.Context, query domain.NodeQuery) (*domain.Package, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, name, version, last_updated_at, health
+		 FROM packages
+		 ORDER BY last_updated_at DESC, id DESC
+		 LIMIT 1`,
+	)
+	return s.scanPackageWithDeps(ctx, row, query)
+}
+
+func (s *Store) GetByName(ctx context.Context, name string, query domain.NodeQuery) (*domain.Package, error) {
+	row := s.db.QueryRowContext(ctx,
+		s.rebind(`SELECT id, name, version, last_updated_at, health
+		 FROM packages
+		 WHERE name = ?`),
+		name,
+	)
+	return s.scanPackageWithDeps(ctx, row, query)
+}
+
+func (s *Store) List(ctx context.Context) ([]*domain.Package, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM packages ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("Query packages error: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("Package scan error: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Package iteration error: %w", err)
+	}
+
+	packages := make([]*domain.Package, 0, len(ids))
+	for _, id := range ids {
+		row := s.db.QueryRowContext(ctx,
+			s.rebind(`SELECT id, name, version, last_updated_at, health
+			 FROM packages
+			 WHERE id = ?`),
+			id,
+		)
+		pkg, err := s.scanPackageWithDeps(ctx, row, domain.NodeQuery{})
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+func (s *Store) DeleteByName(ctx context.Context, name string) error {
+	res, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM packages WHERE name = ?`), name)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func nullString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
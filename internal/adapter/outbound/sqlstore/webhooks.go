@@ -0,0 +1,158 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+
+	if err := s.db.QueryRowContext(ctx,
+		s.rebind(`INSERT INTO webhooks (url, secret, events, format, created_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 RETURNING id`),
+		webhook.URL,
+		webhook.Secret,
+		strings.Join(events, ","),
+		webhook.Format,
+		webhook.CreatedAt,
+	).Scan(&webhook.ID); err != nil {
+		return fmt.Errorf("Insert webhook error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, url, secret, events, format, created_at
+		 FROM webhooks
+		 ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query webhooks error: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []domain.Webhook
+	for rows.Next() {
+		var webhook domain.Webhook
+		var events string
+		if err := rows.Scan(
+			&webhook.ID,
+			&webhook.URL,
+			&webhook.Secret,
+			&events,
+			&webhook.Format,
+			&webhook.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("Webhook scan error: %w", err)
+		}
+		if events != "" {
+			for _, event := range strings.Split(events, ",") {
+				webhook.Events = append(webhook.Events, domain.EventType(event))
+			}
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Webhook iteration error: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Begin Transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`), id); err != nil {
+		return fmt.Errorf("Delete webhook deliveries error: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM webhooks WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("Delete webhook error: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return tx.Commit()
+}
+
+func (s *Store) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if err := s.db.QueryRowContext(ctx,
+		s.rebind(`INSERT INTO webhook_deliveries (webhook_id, event, attempt, status_code, error, success, delivered_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 RETURNING id`),
+		delivery.WebhookID,
+		delivery.Event,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Success,
+		delivery.DeliveredAt,
+	).Scan(&delivery.ID); err != nil {
+		return fmt.Errorf("Insert delivery error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, webhookID int64) ([]domain.WebhookDelivery, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx,
+		s.rebind(`SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = ?)`), webhookID,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("Query webhook error: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		s.rebind(`SELECT id, webhook_id, event, attempt, status_code, error, success, delivered_at
+		 FROM webhook_deliveries
+		 WHERE webhook_id = ?
+		 ORDER BY delivered_at DESC, id DESC`),
+		webhookID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Query deliveries error: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Error,
+			&delivery.Success,
+			&delivery.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("Delivery scan error: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Delivery iteration error: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+const (
+	EventHeader     = "X-Dashboard-Event"
+	SignatureHeader = "X-Dashboard-Signature"
+)
+
+type Config struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+}
+
+type Dispatcher struct {
+	http     *http.Client
+	repo     outbound.WebhookRepository
+	config   Config
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	stopped  bool
+	stopping chan struct{}
+}
+
+func NewDispatcher(httpClient *http.Client, repo outbound.WebhookRepository, cfg Config) *Dispatcher {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Dispatcher{http: httpClient, repo: repo, config: cfg, stopping: make(chan struct{})}
+}
+
+type eventPayload struct {
+	Type       domain.EventType `json:"type"`
+	Package    string           `json:"package"`
+	Version    string           `json:"version,omitempty"`
+	Dependency string           `json:"dependency,omitempty"`
+	Message    string           `json:"message"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, events []domain.Event) {
+	if len(events) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := d.repo.ListWebhooks(ctx)
+	if err != nil {
+		log.Printf("Listing webhooks error: %v", err)
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		log.Printf("Dropping %d event(s), webhook dispatcher is shut down", len(events))
+		return
+	}
+	for _, webhook := range webhooks {
+		for _, event := range events {
+			if !webhook.Subscribes(event.Type) {
+				continue
+			}
+			d.wg.Add(1)
+			go func() {
+				defer d.wg.Done()
+				d.deliver(ctx, webhook, event)
+			}()
+		}
+	}
+}
+
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.stopping)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("Webhook shutdown error: %w", ctx.Err())
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, webhook domain.Webhook, event domain.Event) {
+	body, err := encodePayload(webhook.Format, event)
+	if err != nil {
+		log.Printf("Encoding webhook payload error: %v", err)
+		return
+	}
+
+	backoff := d.config.InitialBackoff
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		delivery := domain.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event: event.Type,
+			Attempt: attempt,
+		}
+		delivery.StatusCode, err = d.send(ctx, webhook, event.Type, body)
+		delivery.DeliveredAt = time.Now().UTC()
+		delivery.Success = err == nil && delivery.StatusCode >= 200 && delivery.StatusCode < 300
+		if err != nil {
+			delivery.Error = err.Error()
+		} else if !delivery.Success {
+			delivery.Error = fmt.Sprintf("Unexpected status: %d", delivery.StatusCode)
+		}
+		if err := d.repo.SaveDelivery(ctx, &delivery); err != nil {
+			log.Printf("Saving webhook delivery error: %v", err)
+		}
+
+		if delivery.Success || !retryable(delivery.StatusCode) || attempt == d.config.MaxAttempts {
+			return
+		}
+		if !d.wait(ctx, backoff) {
+			log.Printf("Webhook %d delivery of %s abandoned after attempt %d", webhook.ID, event.Type, attempt)
+			return
+		}
+		backoff *= 2
+	}
+}
+
+func (d *Dispatcher) wait(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-d.stopping:
+		return false
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook domain.Webhook, event domain.EventType, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("Error building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("Error calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func encodePayload(format domain.WebhookFormat, event domain.Event) ([]byte, error) {
+	if format == domain.WebhookFormatSlack {
+		return json.Marshal(slackPayload{Text: fmt.Sprintf("[dep-dashboard] %s", event.Message)})
+	}
+	return json.Marshal(eventPayload{
+		Type: event.Type,
+		Package: event.Package.Name,
+		Version: event.Package.Version,
+		Dependency: event.Dependency,
+		Message: event.Message,
+		OccurredAt: event.OccurredAt,
+	})
+}
+
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type fakeRepository struct {
+	mu         sync.Mutex
+	webhooks   []domain.Webhook
+	deliveries []domain.WebhookDelivery
+}
+
+func (f *fakeRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	return nil
+}
+
+func (f *fakeRepository) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	return f.webhooks, nil
+}
+
+func (f *fakeRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (f *fakeRepository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries = append(f.deliveries, *delivery)
+	return nil
+}
+
+func (f *fakeRepository) ListDeliveries(ctx context.Context, webhookID int64) ([]domain.WebhookDelivery, error) {
+	return f.deliveries, nil
+}
+
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, expected := r.Header.Get(SignatureHeader), Sign("secret", body); got != expected {
+			t.Errorf("Got signature %s, expected %s", got, expected)
+		}
+		if got := r.Header.Get(EventHeader); got != string(domain.EventPackageRefreshed) {
+			t.Errorf("Got event header %s, expected %s", got, domain.EventPackageRefreshed)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &fakeRepository{webhooks: []domain.Webhook{
+		{ID: 1, URL: server.URL, Secret: "secret", Format: domain.WebhookFormatJSON},
+		{ID: 2, URL: server.URL, Secret: "secret", Events: []domain.EventType{domain.EventPackageDeleted}},
+	}}
+	dispatcher := NewDispatcher(server.Client(), repo, Config{MaxAttempts: 3})
+	dispatcher.Publish(context.Background(), []domain.Event{{
+		Type: domain.EventPackageRefreshed,
+		Package: domain.PackageRef{Name: "express", Version: "5.2.1"},
+		Message: "express 5.2.1 refreshed",
+	}})
+	dispatcher.Wait()
+
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("Got %d deliveries, expected 2", len(repo.deliveries))
+	}
+	first, second := repo.deliveries[0], repo.deliveries[1]
+	if first.Success || first.StatusCode != http.StatusServiceUnavailable || first.Attempt != 1 {
+		t.Errorf("Got first delivery %+v, expected failed attempt 1", first)
+	}
+	if !second.Success || second.Attempt != 2 || second.WebhookID != 1 {
+		t.Errorf("Got second delivery %+v, expected successful attempt 2 of webhook 1", second)
+	}
+}
+
+func TestDispatcherShutdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	repo := &fakeRepository{webhooks: []domain.Webhook{{ID: 1, URL: server.URL, Secret: "secret"}}}
+	dispatcher := NewDispatcher(server.Client(), repo, Config{MaxAttempts: 5, InitialBackoff: time.Hour})
+	event := domain.Event{Type: domain.EventPackageRefreshed, Package: domain.PackageRef{Name: "express"}}
+	dispatcher.Publish(context.Background(), []domain.Event{event})
+
+	for {
+		repo.mu.Lock()
+		sent := len(repo.deliveries)
+		repo.mu.Unlock()
+		if sent > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Errorf("Got %d deliveries, expected retries to stop on shutdown", len(repo.deliveries))
+	}
+
+	dispatcher.Publish(context.Background(), []domain.Event{event})
+	dispatcher.Wait()
+	if len(repo.deliveries) != 1 {
+		t.Errorf("Got %d deliveries, expected events after shutdown to be dropped", len(repo.deliveries))
+	}
+}
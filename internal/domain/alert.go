@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type AlertKind string
+
+const (
+	AlertNewAdvisory    AlertKind = "new_advisory"
+	AlertScoreDropped   AlertKind = "score_dropped"
+	AlertNewLowScore    AlertKind = "new_low_score"
+	AlertVersionChanged AlertKind = "version_changed"
+)
+
+type Alert struct {
+	ID             int64
+	PackageName    string
+	Kind           AlertKind
+	Dependency     string
+	Message        string
+	CreatedAt      time.Time
+	AcknowledgedAt *time.Time
+}
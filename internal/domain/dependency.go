@@ -12,11 +12,56 @@ type DependencyNode struct {
 	Version string
 	Relation string
 	Score *float64
+	Advisories []string
+	Severity *float64
+	License string
+	Depth int
+	FanIn int
+	ProjectKey string
+	Project *Project
+	LatestVersion string
+	Behind VersionLag
+	Deprecated bool
+	DeprecationReason string
+	PublishedAt *time.Time
+	LatestReleaseAt *time.Time
+	Status EnrichmentStatus
+	StatusMessage string
+}
+
+type Edge struct {
+	From PackageRef
+	To PackageRef
+}
+
+type VersionInfo struct {
+	ProjectKey string
+	Advisories []string
+	Licenses []string
+	Deprecated bool
+	DeprecationReason string
+	PublishedAt time.Time
+}
+
+type PackageInfo struct {
+	DefaultVersion string
+	LatestReleaseAt time.Time
+}
+
+type Advisory struct {
+	ID string
+	Title string
+	CVSS3Score float64
 }
 
 type Package struct {
 	ID int64
 	PackageRef PackageRef
 	Dependencies []DependencyNode
+	Edges []Edge
+	Advisories []Advisory
 	LastUpdatedAt time.Time
+	Health *float64
+	Page *Page
+	Refresh *RefreshStats
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type ScorecardCheck struct {
+	Name string
+	Score int
+	Reason string
+	Documentation string
+}
+
+type Scorecard struct {
+	Score float64
+	Date time.Time
+	Checks []ScorecardCheck
+}
+
+type ScorePoint struct {
+	TakenAt time.Time
+	Version string
+	Score *float64
+}
+
+type Dependent struct {
+	Package PackageRef
+	Version string
+	Relation string
+	Depth int
+}
+
+type DependencyDetail struct {
+	Package PackageRef
+	Name string
+	Versions []DependencyNode
+	Paths [][]PackageRef
+	ScoreHistory []ScorePoint
+	Scorecard *Scorecard
+	Advisories []Advisory
+	SourceURL string
+	Project *Project
+	UsedBy []Dependent
+}
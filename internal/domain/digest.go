@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type Digest struct {
+	From           time.Time
+	To             time.Time
+	Packages       []PackageSummary
+	VersionChanges []Alert
+	ScoreChanges   []Alert
+	NewAdvisories  []Alert
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "strings"
+
+type EnrichmentStatus string
+
+const (
+	EnrichmentScored      EnrichmentStatus = "scored"
+	EnrichmentNoProject   EnrichmentStatus = "no_project"
+	EnrichmentNoScorecard EnrichmentStatus = "no_scorecard"
+	EnrichmentNotFound    EnrichmentStatus = "not_found"
+	EnrichmentFetchError  EnrichmentStatus = "fetch_error"
+)
+
+func (s EnrichmentStatus) Label() string {
+	return strings.ReplaceAll(string(s), "_", " ")
+}
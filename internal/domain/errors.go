@@ -1,5 +1,61 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
-var ErrNotFound = errors.New("Package not found")
\ No newline at end of file
+type ErrorKind string
+
+const (
+	KindNotFound            ErrorKind = "not_found"
+	KindUpstreamUnavailable ErrorKind = "upstream_unavailable"
+	KindUpstreamNotFound    ErrorKind = "upstream_not_found"
+	KindInvalidInput        ErrorKind = "invalid_input"
+	KindConflict            ErrorKind = "conflict"
+)
+
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewError(kind ErrorKind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+func Errorf(kind ErrorKind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+type LookupError[K comparable] struct {
+	Failed map[K]error
+}
+
+func (e *LookupError[K]) Error() string {
+	return fmt.Sprintf("Batch lookup fell back to single lookups, %d failed", len(e.Failed))
+}
+
+func KindOf(err error) ErrorKind {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed.Kind
+	}
+	return ""
+}
+
+var (
+	ErrNotFound = Errorf(KindNotFound, "Package not found")
+	ErrDependencyNotFound = Errorf(KindNotFound, "Dependency not found")
+	ErrAlertNotFound = Errorf(KindNotFound, "Alert not found")
+	ErrWebhookNotFound = Errorf(KindNotFound, "Webhook not found")
+	ErrInvalidWebhook = Errorf(KindInvalidInput, "Invalid webhook")
+)
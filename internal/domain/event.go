@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventPackageRefreshed EventType = "package.refreshed"
+	EventVersionChanged   EventType = "package.version_changed"
+	EventScoreDropped     EventType = "dependency.score_dropped"
+	EventPackageDeleted   EventType = "package.deleted"
+)
+
+var EventTypes = []EventType{
+	EventPackageRefreshed,
+	EventVersionChanged,
+	EventScoreDropped,
+	EventPackageDeleted,
+}
+
+type Event struct {
+	Type       EventType
+	Package    PackageRef
+	Dependency string
+	Message    string
+	OccurredAt time.Time
+}
@@ -4,11 +4,32 @@ type Operator string
 
 const (
 	FilterEq Operator = "eq"
+	FilterNe Operator = "ne"
+	FilterLt Operator = "lt"
+	FilterLte Operator = "lte"
+	FilterGt Operator = "gt"
 	FilterGte Operator = "gte"
+	FilterIn Operator = "in"
+	FilterPrefix Operator = "prefix"
+	FilterLike Operator = "like"
+	FilterIsNull Operator = "isnull"
+)
+
+type Logic string
+
+const (
+	LogicAnd Logic = "and"
+	LogicOr Logic = "or"
 )
 
 type Filter struct {
 	Column string
 	Operator Operator
 	Value string
-}
\ No newline at end of file
+	Logic Logic
+	Filters []Filter
+}
+
+func (f Filter) IsGroup() bool {
+	return len(f.Filters) > 0
+}
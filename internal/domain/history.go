@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ChangeKind string
+
+const (
+	ChangeVersion ChangeKind = "version_changed"
+	ChangeAdded   ChangeKind = "dependency_added"
+	ChangeRemoved ChangeKind = "dependency_removed"
+	ChangeUpdated ChangeKind = "dependency_updated"
+	ChangeScore   ChangeKind = "score_changed"
+)
+
+type Change struct {
+	Kind       ChangeKind
+	Dependency string
+	From       string
+	To         string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeVersion:
+		return fmt.Sprintf("Default version changed from %s to %s", c.From, c.To)
+	case ChangeAdded:
+		return fmt.Sprintf("Added %s %s", c.Dependency, c.To)
+	case ChangeRemoved:
+		return fmt.Sprintf("Removed %s %s", c.Dependency, c.From)
+	case ChangeUpdated:
+		return fmt.Sprintf("Updated %s from %s to %s", c.Dependency, c.From, c.To)
+	case ChangeScore:
+		return fmt.Sprintf("%s score changed from %s to %s", c.Dependency, c.From, c.To)
+	default:
+		return fmt.Sprintf("%s %s", c.Kind, c.Dependency)
+	}
+}
+
+type Snapshot struct {
+	ID         int64
+	PackageRef PackageRef
+	TakenAt    time.Time
+	Health     *float64
+	Changes    []Change
+}
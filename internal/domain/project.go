@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+var sourceHosts = []string{"github.com/", "gitlab.com/", "bitbucket.org/"}
+
+type Project struct {
+	Key string
+	Description string
+	Homepage string
+	License string
+	Stars int
+	Forks int
+	OpenIssues int
+	OSSFuzz bool
+	Scorecard Scorecard
+	FetchedAt time.Time
+}
+
+func (p Project) HasScorecard() bool {
+	return !p.Scorecard.Date.IsZero()
+}
+
+func SourceURL(projectKey string) string {
+	for _, host := range sourceHosts {
+		if strings.HasPrefix(projectKey, host) {
+			return "https://" + projectKey
+		}
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "testing"
+
+func TestSourceURL(t *testing.T) {
+	tests := []struct {
+		key string
+		expected string
+	}{
+		{key: "github.com/expressjs/express", expected: "https://github.com/expressjs/express"},
+		{key: "gitlab.com/group/project", expected: "https://gitlab.com/group/project"},
+		{key: "bitbucket.org/team/repo", expected: "https://bitbucket.org/team/repo"},
+		{key: "example.com/self-hosted/repo", expected: ""},
+		{key: "github.company.com/org/repo", expected: ""},
+		{key: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := SourceURL(tt.key); got != tt.expected {
+				t.Errorf("Got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
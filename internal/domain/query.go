@@ -0,0 +1,19 @@
+package domain
+
+type Sort struct {
+	Column string
+	Desc bool
+}
+
+type NodeQuery struct {
+	Filters []Filter
+	Sort Sort
+	Limit int
+	Cursor string
+}
+
+type Page struct {
+	Total int
+	Limit int
+	NextCursor string
+}
@@ -0,0 +1,7 @@
+package domain
+
+type RefreshStats struct {
+	Reused     int
+	Enriched   int
+	CallsSaved int
+}
@@ -0,0 +1,24 @@
+package domain
+
+type ScoreBand string
+
+const (
+	ScoreBandGreen  ScoreBand = "green"
+	ScoreBandYellow ScoreBand = "yellow"
+	ScoreBandRed    ScoreBand = "red"
+	ScoreBandNil    ScoreBand = "nil"
+)
+
+func ScoreBandOf(score *float64) ScoreBand {
+	if score == nil {
+		return ScoreBandNil
+	}
+	switch {
+	case *score >= 7.5:
+		return ScoreBandGreen
+	case *score >= 4.0:
+		return ScoreBandYellow
+	default:
+		return ScoreBandRed
+	}
+}
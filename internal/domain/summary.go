@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+type PackageSummary struct {
+	PackageRef    PackageRef
+	LastUpdatedAt time.Time
+	Health        *float64
+	Dependencies  int
+	Scored        int
+	AverageScore  *float64
+	MinScore      *float64
+	Advisories    int
+	Unscored      []DependencyNode
+	Vulnerable    []DependencyNode
+	Deprecated    []DependencyNode
+	Failed        []DependencyNode
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type VersionLag struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (l VersionLag) Outdated() bool {
+	return l.Major > 0 || l.Minor > 0 || l.Patch > 0
+}
+
+func (l VersionLag) String() string {
+	switch {
+	case l.Major > 0:
+		return fmt.Sprintf("%d major behind", l.Major)
+	case l.Minor > 0:
+		return fmt.Sprintf("%d minor behind", l.Minor)
+	case l.Patch > 0:
+		return fmt.Sprintf("%d patch behind", l.Patch)
+	default:
+		return "up to date"
+	}
+}
+
+func DaysSince(t *time.Time, now time.Time) *int {
+	if t == nil {
+		return nil
+	}
+	days := max(int(now.Sub(*t).Hours()/24), 0)
+	return &days
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaysSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) *time.Time {
+		return &t
+	}
+
+	tests := []struct {
+		name string
+		t *time.Time
+		expected int
+	}{
+		{name: "same moment", t: at(now), expected: 0},
+		{name: "partial day is not counted", t: at(now.Add(-23 * time.Hour)), expected: 0},
+		{name: "days", t: at(now.Add(-9*24*time.Hour - time.Hour)), expected: 9},
+		{name: "years", t: at(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)), expected: 1096},
+		{name: "future is clamped", t: at(now.Add(48 * time.Hour)), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DaysSince(tt.t, now); got == nil || *got != tt.expected {
+				t.Errorf("Got %v, expected %d", got, tt.expected)
+			}
+		})
+	}
+	if got := DaysSince(nil, now); got != nil {
+		t.Errorf("Got %d, expected nil for unknown date", *got)
+	}
+}
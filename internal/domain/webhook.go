@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type WebhookFormat string
+
+const (
+	WebhookFormatJSON  WebhookFormat = "json"
+	WebhookFormatSlack WebhookFormat = "slack"
+)
+
+type Webhook struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []EventType
+	Format    WebhookFormat
+	CreatedAt time.Time
+}
+
+func (w Webhook) Subscribes(event EventType) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+type WebhookDelivery struct {
+	ID          int64
+	WebhookID   int64
+	Event       EventType
+	Attempt     int
+	StatusCode  int
+	Error       string
+	Success     bool
+	DeliveredAt time.Time
+}
@@ -9,6 +9,18 @@ import (
 
 type DependencyService interface {
 	StoreDependencies(ctx context.Context, name string) (*domain.Package, error)
-	GetDependencies(ctx context.Context, filters []domain.Filter) (*domain.Package, error)
+	RetryFailed(ctx context.Context, name string) (*domain.Package, error)
+	GetDependencies(ctx context.Context, name string, query domain.NodeQuery) (*domain.Package, error)
+	GetDependencyDetail(ctx context.Context, name string, dependency string) (*domain.DependencyDetail, error)
+	FindDependents(ctx context.Context, dependency string, version string) ([]domain.Dependent, error)
 	DeleteDependenciesByName(ctx context.Context, name string) (error)	
+	ListPackages(ctx context.Context) ([]domain.PackageSummary, error)
+	SummarizePackage(ctx context.Context, name string) (*domain.PackageSummary, error)
+	ListHistory(ctx context.Context, name string, limit int) ([]domain.Snapshot, error)
+	ListAlerts(ctx context.Context, includeAcknowledged bool) ([]domain.Alert, error)
+	AcknowledgeAlert(ctx context.Context, id int64) error
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	ListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListWebhookDeliveries(ctx context.Context, id int64) ([]domain.WebhookDelivery, error)
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+package inbound
+
+import (
+	"context"
+	"time"
+)
+
+type DigestService interface {
+	SendDigest(ctx context.Context) error
+	NextDigest(ctx context.Context) (time.Time, error)
+}
@@ -0,0 +1,14 @@
+package outbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type AlertRepository interface {
+	SaveAlerts(ctx context.Context, alerts []domain.Alert) error
+	ListAlerts(ctx context.Context, includeAcknowledged bool, since time.Time) ([]domain.Alert, error)
+	AcknowledgeAlert(ctx context.Context, id int64) error
+}
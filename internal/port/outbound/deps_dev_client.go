@@ -6,9 +6,15 @@ import (
 	"github.com/JCzapla/dep-dashboard/internal/domain"
 )
 
+const BatchLimit = 5000
+
 type DepsDevClient interface {
 	FetchDefaultVersion(ctx context.Context, name string) (string, error)
-	FetchDependencies(ctx context.Context, ref domain.PackageRef) ([]domain.DependencyNode, error)
-	FetchProjectKey(ctx context.Context, ref domain.PackageRef) (string, error)
-	FetchScore(ctx context.Context, projectKey string) (float64, error)
+	FetchPackage(ctx context.Context, name string) (domain.PackageInfo, error)
+	FetchDependencies(ctx context.Context, ref domain.PackageRef) ([]domain.DependencyNode, []domain.Edge, error)
+	FetchVersion(ctx context.Context, ref domain.PackageRef) (domain.VersionInfo, error)
+	FetchVersions(ctx context.Context, refs []domain.PackageRef) (map[domain.PackageRef]domain.VersionInfo, error)
+	FetchAdvisory(ctx context.Context, id string) (domain.Advisory, error)
+	FetchProject(ctx context.Context, projectKey string) (domain.Project, error)
+	FetchProjects(ctx context.Context, projectKeys []string) (map[string]domain.Project, error)
 }
\ No newline at end of file
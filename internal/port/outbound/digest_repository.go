@@ -0,0 +1,11 @@
+package outbound
+
+import (
+	"context"
+	"time"
+)
+
+type DigestRepository interface {
+	LastDigestSent(ctx context.Context) (*time.Time, error)
+	SaveDigestSent(ctx context.Context, sentAt time.Time) error
+}
@@ -0,0 +1,11 @@
+package outbound
+
+import (
+	"context"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type DigestSender interface {
+	SendDigest(ctx context.Context, digest *domain.Digest) error
+}
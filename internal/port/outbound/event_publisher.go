@@ -0,0 +1,11 @@
+package outbound
+
+import (
+	"context"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, events []domain.Event)
+}
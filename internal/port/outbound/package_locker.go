@@ -0,0 +1,8 @@
+package outbound
+
+import "context"
+
+type PackageLocker interface {
+	LockPackage(ctx context.Context, name string) (func(), error)
+	TryLockPackage(ctx context.Context, name string) (func(), bool, error)
+}
@@ -8,7 +8,16 @@ import (
 
 
 type Repository interface {
-	Save(ctx context.Context, pkg *domain.Package) error
-	GetCurrent(ctx context.Context, filters []domain.Filter) (*domain.Package, error)
+	Save(ctx context.Context, pkg *domain.Package, changes []domain.Change) error
+	GetCurrent(ctx context.Context, query domain.NodeQuery) (*domain.Package, error)
+	GetByName(ctx context.Context, name string, query domain.NodeQuery) (*domain.Package, error)
+	List(ctx context.Context) ([]*domain.Package, error)
 	DeleteByName(ctx context.Context, name string) (error)
+	ListSnapshots(ctx context.Context, name string, limit int) ([]domain.Snapshot, error)
+	PruneSnapshots(ctx context.Context, name string, keep int) error
+	ListEdges(ctx context.Context, name string) ([]domain.Edge, error)
+	ListScoreHistory(ctx context.Context, name string, dependency string) ([]domain.ScorePoint, error)
+	GetProjects(ctx context.Context, keys []string) (map[string]domain.Project, error)
+	GetAdvisories(ctx context.Context, ids []string) (map[string]domain.Advisory, error)
+	FindDependents(ctx context.Context, dependency string, version string) ([]domain.Dependent, error)
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package outbound
+
+import (
+	"context"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	ListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID int64) ([]domain.WebhookDelivery, error)
+}
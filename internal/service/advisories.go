@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+type advisoryCache struct {
+	client outbound.DepsDevClient
+	mu sync.Mutex
+	scores map[string]*float64
+	advisories []domain.Advisory
+}
+
+func newAdvisoryCache(client outbound.DepsDevClient) *advisoryCache {
+	return &advisoryCache{client: client, scores: make(map[string]*float64)}
+}
+
+func (c *advisoryCache) maxSeverity(ctx context.Context, ids []string) *float64 {
+	var max *float64
+	for _, id := range ids {
+		score := c.severity(ctx, id)
+		if score != nil && (max == nil || *score > *max) {
+			max = score
+		}
+	}
+	return max
+}
+
+func (c *advisoryCache) severity(ctx context.Context, id string) *float64 {
+	c.mu.Lock()
+	score, ok := c.scores[id]
+	c.mu.Unlock()
+	if ok {
+		return score
+	}
+
+	advisory, err := c.client.FetchAdvisory(ctx, id)
+	if err == nil {
+		score = &advisory.CVSS3Score
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.scores[id]; !ok && err == nil {
+		c.advisories = append(c.advisories, advisory)
+	}
+	c.scores[id] = score
+	return score
+}
+
+func (c *advisoryCache) fetched() []domain.Advisory {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.advisories
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func detectAlerts(oldPkg, newPkg *domain.Package, threshold float64) []domain.Alert {
+	now := time.Now().UTC()
+	name := newPkg.PackageRef.Name
+	var alerts []domain.Alert
+	add := func(kind domain.AlertKind, dependency, message string) {
+		alerts = append(alerts, domain.Alert{
+			PackageName: name,
+			Kind: kind,
+			Dependency: dependency,
+			Message: message,
+			CreatedAt: now,
+		})
+	}
+
+	if oldPkg.PackageRef.Version != newPkg.PackageRef.Version {
+		add(domain.AlertVersionChanged, name, fmt.Sprintf("%s default version changed from %s to %s",
+			name, oldPkg.PackageRef.Version, newPkg.PackageRef.Version))
+	}
+
+	pairs, _ := pairNodes(oldPkg.Dependencies, newPkg.Dependencies)
+	for i, node := range newPkg.Dependencies {
+		old := pairs[i]
+		for _, advisory := range node.Advisories {
+			if old == nil || !slices.Contains(old.Advisories, advisory) {
+				add(domain.AlertNewAdvisory, node.Name, fmt.Sprintf("%s %s is affected by advisory %s",
+					node.Name, node.Version, advisory))
+			}
+		}
+
+		if node.Score == nil || *node.Score >= threshold {
+			continue
+		}
+		switch {
+		case old == nil:
+			add(domain.AlertNewLowScore, node.Name, fmt.Sprintf("New dependency %s %s has a low score of %.1f",
+				node.Name, node.Version, *node.Score))
+		case old.Score == nil:
+			add(domain.AlertNewLowScore, node.Name, fmt.Sprintf("%s %s got its first score, a low one of %.1f",
+				node.Name, node.Version, *node.Score))
+		case *old.Score >= threshold:
+			add(domain.AlertScoreDropped, node.Name, fmt.Sprintf("%s score dropped from %.1f to %.1f",
+				node.Name, *old.Score, *node.Score))
+		}
+	}
+	return alerts
+}
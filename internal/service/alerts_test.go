@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func score(v float64) *float64 {
+	return &v
+}
+
+func TestDetectAlerts(t *testing.T) {
+	oldPkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "express", Version: "5.1.0"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "qs", Version: "6.13.0", Score: score(6.0), Advisories: []string{"GHSA-old"}},
+			{Name: "debug", Version: "4.4.0", Score: score(8.0)},
+			{Name: "ms", Version: "2.1.3", Score: score(3.0)},
+			{Name: "pending", Version: "1.0.0"},
+		},
+	}
+	newPkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "express", Version: "5.2.1"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "qs", Version: "6.14.0", Score: score(6.0), Advisories: []string{"GHSA-old", "GHSA-new"}},
+			{Name: "debug", Version: "4.4.1", Score: score(3.5)},
+			{Name: "ms", Version: "2.1.3", Score: score(2.0)},
+			{Name: "left-pad", Version: "1.3.0", Score: score(1.2)},
+			{Name: "pending", Version: "1.0.0", Score: score(2.5)},
+			{Name: "unscored", Version: "1.0.0"},
+		},
+	}
+
+	alerts := detectAlerts(oldPkg, newPkg, 4.0)
+
+	expected := []struct {
+		kind domain.AlertKind
+		dependency string
+	}{
+		{domain.AlertVersionChanged, "express"},
+		{domain.AlertNewAdvisory, "qs"},
+		{domain.AlertScoreDropped, "debug"},
+		{domain.AlertNewLowScore, "left-pad"},
+		{domain.AlertNewLowScore, "pending"},
+	}
+	if len(alerts) != len(expected) {
+		t.Fatalf("Got %d alerts, expected %d: %+v", len(alerts), len(expected), alerts)
+	}
+	for i, e := range expected {
+		if alerts[i].Kind != e.kind || alerts[i].Dependency != e.dependency {
+			t.Errorf("Got alerts[%d] %s/%s, expected %s/%s", i, alerts[i].Kind, alerts[i].Dependency, e.kind, e.dependency)
+		}
+		if alerts[i].PackageName != "express" {
+			t.Errorf("Got alerts[%d] package %s, expected express", i, alerts[i].PackageName)
+		}
+	}
+}
+
+func TestDetectAlertsMultipleVersions(t *testing.T) {
+	oldPkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "qs", Version: "6.14.0", Score: score(6.0), Advisories: []string{"GHSA-1"}},
+			{Name: "qs", Version: "5.2.1", Score: score(6.0)},
+		},
+	}
+	newPkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "qs", Version: "6.14.0", Score: score(6.0), Advisories: []string{"GHSA-1"}},
+			{Name: "qs", Version: "5.2.1", Score: score(3.0), Advisories: []string{"GHSA-2"}},
+		},
+	}
+
+	alerts := detectAlerts(oldPkg, newPkg, 4.0)
+	expected := []domain.AlertKind{domain.AlertNewAdvisory, domain.AlertScoreDropped}
+	if len(alerts) != len(expected) {
+		t.Fatalf("Got %d alerts, expected %d: %+v", len(alerts), len(expected), alerts)
+	}
+	for i, kind := range expected {
+		if alerts[i].Kind != kind {
+			t.Errorf("Got alerts[%d] %s, expected %s", i, alerts[i].Kind, kind)
+		}
+	}
+	if alerts[0].Message != "qs 5.2.1 is affected by advisory GHSA-2" {
+		t.Errorf("Got message %q, expected advisory of the version that got it", alerts[0].Message)
+	}
+
+	if events := refreshEvents(oldPkg, newPkg); len(events) != 2 || events[1].Type != domain.EventScoreDropped {
+		t.Errorf("Got events %+v, expected refresh and score drop of qs 5.2.1", events)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func pairNodes(oldNodes, newNodes []domain.DependencyNode) ([]*domain.DependencyNode, []domain.DependencyNode) {
+	exact := make(map[domain.PackageRef]int, len(oldNodes))
+	for i, node := range oldNodes {
+		exact[domain.PackageRef{Name: node.Name, Version: node.Version}] = i
+	}
+	used := make([]bool, len(oldNodes))
+	pairs := make([]*domain.DependencyNode, len(newNodes))
+	for i, node := range newNodes {
+		if j, ok := exact[domain.PackageRef{Name: node.Name, Version: node.Version}]; ok && !used[j] {
+			used[j] = true
+			pairs[i] = &oldNodes[j]
+		}
+	}
+
+	leftovers := make(map[string][]int)
+	for j, node := range oldNodes {
+		if !used[j] {
+			leftovers[node.Name] = append(leftovers[node.Name], j)
+		}
+	}
+	for i, node := range newNodes {
+		if pairs[i] != nil || len(leftovers[node.Name]) == 0 {
+			continue
+		}
+		j := leftovers[node.Name][0]
+		leftovers[node.Name] = leftovers[node.Name][1:]
+		used[j] = true
+		pairs[i] = &oldNodes[j]
+	}
+
+	var removed []domain.DependencyNode
+	for j, node := range oldNodes {
+		if !used[j] {
+			removed = append(removed, node)
+		}
+	}
+	return pairs, removed
+}
+
+func diffPackages(oldPkg, newPkg *domain.Package) []domain.Change {
+	if oldPkg == nil {
+		return nil
+	}
+
+	var changes []domain.Change
+	if oldPkg.PackageRef.Version != newPkg.PackageRef.Version {
+		changes = append(changes, domain.Change{
+			Kind: domain.ChangeVersion,
+			Dependency: newPkg.PackageRef.Name,
+			From: oldPkg.PackageRef.Version,
+			To: newPkg.PackageRef.Version,
+		})
+	}
+
+	pairs, removed := pairNodes(oldPkg.Dependencies, newPkg.Dependencies)
+	for i, node := range newPkg.Dependencies {
+		old := pairs[i]
+		if old == nil {
+			changes = append(changes, domain.Change{Kind: domain.ChangeAdded, Dependency: node.Name, To: node.Version})
+			continue
+		}
+		if old.Version != node.Version && node.Relation != "SELF" {
+			changes = append(changes, domain.Change{
+				Kind: domain.ChangeUpdated,
+				Dependency: node.Name,
+				From: old.Version,
+				To: node.Version,
+			})
+		}
+		if old.Score != nil && node.Score != nil && *old.Score != *node.Score {
+			changes = append(changes, domain.Change{
+				Kind: domain.ChangeScore,
+				Dependency: node.Name,
+				From: fmt.Sprintf("%.1f", *old.Score),
+				To: fmt.Sprintf("%.1f", *node.Score),
+			})
+		}
+	}
+	for _, node := range removed {
+		changes = append(changes, domain.Change{Kind: domain.ChangeRemoved, Dependency: node.Name, From: node.Version})
+	}
+	return changes
+}
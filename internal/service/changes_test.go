@@ -0,0 +1,70 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestDiffPackages(t *testing.T) {
+	node := func(name, version string, value float64) domain.DependencyNode {
+		return domain.DependencyNode{Name: name, Version: version, Score: score(value)}
+	}
+	root := domain.PackageRef{Name: "app", Version: "1.0.0"}
+
+	tests := []struct {
+		name string
+		previous []domain.DependencyNode
+		current []domain.DependencyNode
+		expected []domain.Change
+	}{
+		{
+			name: "updated version",
+			previous: []domain.DependencyNode{node("qs", "6.13.0", 6)},
+			current: []domain.DependencyNode{node("qs", "6.14.0", 6)},
+			expected: []domain.Change{{Kind: domain.ChangeUpdated, Dependency: "qs", From: "6.13.0", To: "6.14.0"}},
+		},
+		{
+			name: "unchanged versions of one package",
+			previous: []domain.DependencyNode{node("qs", "6.14.0", 6), node("qs", "5.2.1", 6)},
+			current: []domain.DependencyNode{node("qs", "5.2.1", 6), node("qs", "6.14.0", 6)},
+		},
+		{
+			name: "one of several versions updated",
+			previous: []domain.DependencyNode{node("qs", "6.13.0", 6), node("qs", "5.2.1", 6)},
+			current: []domain.DependencyNode{node("qs", "6.14.0", 6), node("qs", "5.2.1", 6)},
+			expected: []domain.Change{{Kind: domain.ChangeUpdated, Dependency: "qs", From: "6.13.0", To: "6.14.0"}},
+		},
+		{
+			name: "another version added",
+			previous: []domain.DependencyNode{node("qs", "6.14.0", 6)},
+			current: []domain.DependencyNode{node("qs", "5.2.1", 6), node("qs", "6.14.0", 6)},
+			expected: []domain.Change{{Kind: domain.ChangeAdded, Dependency: "qs", To: "5.2.1"}},
+		},
+		{
+			name: "one of several versions removed",
+			previous: []domain.DependencyNode{node("qs", "5.2.1", 6), node("qs", "6.14.0", 6)},
+			current: []domain.DependencyNode{node("qs", "6.14.0", 6)},
+			expected: []domain.Change{{Kind: domain.ChangeRemoved, Dependency: "qs", From: "5.2.1"}},
+		},
+		{
+			name: "score compared with the same version",
+			previous: []domain.DependencyNode{node("qs", "6.14.0", 6), node("qs", "5.2.1", 3)},
+			current: []domain.DependencyNode{node("qs", "6.14.0", 6), node("qs", "5.2.1", 4)},
+			expected: []domain.Change{{Kind: domain.ChangeScore, Dependency: "qs", From: "3.0", To: "4.0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := diffPackages(
+				&domain.Package{PackageRef: root, Dependencies: tt.previous},
+				&domain.Package{PackageRef: root, Dependencies: tt.current},
+			)
+			if !slices.Equal(changes, tt.expected) {
+				t.Errorf("Got changes %+v, expected %+v", changes, tt.expected)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,21 +15,61 @@ import (
 
 const workerLimit = 10
 
+type Config struct {
+	AlertScoreThreshold float64
+	Health HealthWeights
+	ProjectMaxAge time.Duration
+	SnapshotRetention int
+}
+
 type DependencyService struct {
 	repo outbound.Repository
+	alerts outbound.AlertRepository
+	webhooks outbound.WebhookRepository
 	client outbound.DepsDevClient
+	events outbound.EventPublisher
+	config Config
+	refreshes *refreshGroup
+	locks *packageLocks
 }
 
-func NewDependencyService(repo outbound.Repository, client outbound.DepsDevClient) *DependencyService {
-	return &DependencyService{repo: repo, client: client}
+func NewDependencyService(
+	repo outbound.Repository,
+	alerts outbound.AlertRepository,
+	webhooks outbound.WebhookRepository,
+	client outbound.DepsDevClient,
+	events outbound.EventPublisher,
+	cfg Config,
+) *DependencyService {
+	locker, _ := repo.(outbound.PackageLocker)
+	return &DependencyService{
+		repo: repo,
+		alerts: alerts,
+		webhooks: webhooks,
+		client: client,
+		events: events,
+		config: cfg,
+		refreshes: newRefreshGroup(),
+		locks: newPackageLocks(locker),
+	}
 }
 
 func (s *DependencyService) StoreDependencies(ctx context.Context, name string) (*domain.Package, error) {
-	oldPackage, err := s.repo.GetCurrent(ctx, nil)
+	return s.refreshes.do(ctx, name, func(ctx context.Context) (*domain.Package, error) {
+		unlock, err := s.locks.lock(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+		return s.storeDependencies(ctx, name)
+	})
+}
+
+func (s *DependencyService) storeDependencies(ctx context.Context, name string) (*domain.Package, error) {
+	previous, err := s.repo.GetByName(ctx, name, domain.NodeQuery{})
 	if err != nil && err != domain.ErrNotFound {
 		return nil, err
 	}
-	
 
 	defaultVersion, err := s.client.FetchDefaultVersion(ctx, name)
 	if err != nil {
@@ -38,69 +81,288 @@ func (s *DependencyService) StoreDependencies(ctx context.Context, name string)
 		Version: defaultVersion,
 	}
 
-	nodes, err := s.client.FetchDependencies(ctx, ref)
+	nodes, edges, err := s.client.FetchDependencies(ctx, ref)
 	if err != nil {
 		return nil, err
 	} 
 
-	s.enrichWithScores(ctx, nodes)
+	stats, advisories := s.refreshNodes(ctx, previous, nodes)
 
 	pkg := &domain.Package{
 		PackageRef: ref,
 		Dependencies: nodes,
+		Edges: edges,
+		Advisories: advisories,
 		LastUpdatedAt: time.Now().UTC(),
+		Refresh: stats,
+	}
+	return s.save(ctx, previous, pkg)
+}
+
+func (s *DependencyService) RetryFailed(ctx context.Context, name string) (*domain.Package, error) {
+	unlock, ok, err := s.locks.tryLock(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.Errorf(domain.KindConflict, "%s is being refreshed or modified, retry once it finishes", name)
+	}
+	defer unlock()
+
+	previous, err := s.repo.GetByName(ctx, name, domain.NodeQuery{})
+	if err != nil {
+		return nil, err
+	}
+	edges, err := s.repo.ListEdges(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]domain.DependencyNode, len(previous.Dependencies))
+	copy(nodes, previous.Dependencies)
+	var failed []domain.DependencyNode
+	var positions []int
+	for i, node := range nodes {
+		if node.Status != domain.EnrichmentFetchError {
+			continue
+		}
+		failed = append(failed, domain.DependencyNode{
+			Name: node.Name,
+			Version: node.Version,
+			Relation: node.Relation,
+			Depth: node.Depth,
+			FanIn: node.FanIn,
+		})
+		positions = append(positions, i)
 	}
 
-	if err := s.repo.Save(ctx, pkg); err != nil {
+	advisories, _ := s.enrichWithScores(ctx, failed)
+	for i, position := range positions {
+		nodes[position] = failed[i]
+	}
+	pkg := &domain.Package{
+		PackageRef: previous.PackageRef,
+		Dependencies: nodes,
+		Edges: edges,
+		Advisories: advisories,
+		LastUpdatedAt: time.Now().UTC(),
+		Refresh: &domain.RefreshStats{Reused: len(nodes) - len(failed), Enriched: len(failed)},
+	}
+	return s.save(ctx, previous, pkg)
+}
+
+func (s *DependencyService) save(ctx context.Context, previous *domain.Package, pkg *domain.Package) (*domain.Package, error) {
+	pkg.Health = healthScore(pkg, s.config.Health)
+
+	if err := s.repo.Save(ctx, pkg, diffPackages(previous, pkg)); err != nil {
 		return nil, fmt.Errorf("Saving package error: %w", err)
 	}
-	if oldPackage != nil && pkg.PackageRef.Name != oldPackage.PackageRef.Name {
-		err = s.DeleteDependenciesByName(ctx, oldPackage.PackageRef.Name)
-		if err != nil {
-			return nil, err
-		} 
+	if s.config.SnapshotRetention > 0 {
+		if err := s.repo.PruneSnapshots(ctx, pkg.PackageRef.Name, s.config.SnapshotRetention); err != nil {
+			log.Printf("Snapshot pruning skipped: %v", err)
+		}
+	}
+	if previous != nil {
+		alerts := detectAlerts(previous, pkg, s.config.AlertScoreThreshold)
+		if err := s.alerts.SaveAlerts(ctx, alerts); err != nil {
+			return nil, fmt.Errorf("Saving alerts error: %w", err)
+		}
 	}
+	s.events.Publish(ctx, refreshEvents(previous, pkg))
 	return pkg, nil
 }
 
-func (s *DependencyService) GetDependencies(ctx context.Context, filters []domain.Filter) (*domain.Package, error) {
-	return s.repo.GetCurrent(ctx, filters)
+func (s *DependencyService) GetDependencies(ctx context.Context, name string, query domain.NodeQuery) (*domain.Package, error) {
+	if name != "" {
+		return s.repo.GetByName(ctx, name, query)
+	}
+	return s.repo.GetCurrent(ctx, query)
 
 }
 
 func (s *DependencyService) DeleteDependenciesByName(ctx context.Context, name string) (error) {
-	return s.repo.DeleteByName(ctx, name)
+	unlock, err := s.locks.lock(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.repo.DeleteByName(ctx, name); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, []domain.Event{{
+		Type: domain.EventPackageDeleted,
+		Package: domain.PackageRef{Name: name},
+		Message: fmt.Sprintf("%s was removed from the dashboard", name),
+		OccurredAt: time.Now().UTC(),
+	}})
+	return nil
 }
 
-func (s *DependencyService) enrichWithScores(ctx context.Context, nodes []domain.DependencyNode) {
-	guard := make(chan struct{}, workerLimit)
-	var wg sync.WaitGroup
-	wg.Add(len(nodes))
+func (s *DependencyService) ListPackages(ctx context.Context) ([]domain.PackageSummary, error) {
+	packages, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]domain.PackageSummary, len(packages))
+	for i, pkg := range packages {
+		summaries[i] = summarize(pkg)
+	}
+	return summaries, nil
+}
+
+func (s *DependencyService) SummarizePackage(ctx context.Context, name string) (*domain.PackageSummary, error) {
+	pkg, err := s.repo.GetByName(ctx, name, domain.NodeQuery{})
+	if err != nil {
+		return nil, err
+	}
+	summary := summarize(pkg)
+	return &summary, nil
+}
+
+func (s *DependencyService) ListHistory(ctx context.Context, name string, limit int) ([]domain.Snapshot, error) {
+	snapshots, err := s.repo.ListSnapshots(ctx, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 && name != "" {
+		if _, err := s.repo.GetByName(ctx, name, domain.NodeQuery{Limit: 1}); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+func (s *DependencyService) ListAlerts(ctx context.Context, includeAcknowledged bool) ([]domain.Alert, error) {
+	return s.alerts.ListAlerts(ctx, includeAcknowledged, time.Time{})
+}
+
+func (s *DependencyService) AcknowledgeAlert(ctx context.Context, id int64) error {
+	return s.alerts.AcknowledgeAlert(ctx, id)
+}
+
+func (s *DependencyService) enrichWithScores(ctx context.Context, nodes []domain.DependencyNode) ([]domain.Advisory, lookups) {
+	if len(nodes) == 0 {
+		return nil, lookups{}
+	}
+	refs := make([]domain.PackageRef, len(nodes))
+	for i, node := range nodes {
+		refs[i] = domain.PackageRef{Name: node.Name, Version: node.Version}
+	}
+	versions, versionErr := s.client.FetchVersions(ctx, refs)
+
+	var names, ids, keys []string
+	seenNames, seenIds, seenKeys := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for i, node := range nodes {
+		names = appendUnique(names, seenNames, node.Name)
+		info := versions[refs[i]]
+		for _, id := range info.Advisories {
+			ids = appendUnique(ids, seenIds, id)
+		}
+		if info.ProjectKey != "" {
+			keys = appendUnique(keys, seenKeys, info.ProjectKey)
+		}
+	}
+
+	packages := newPackageCache(s.client)
+	advisories := newAdvisoryCache(s.client)
+	parallel(len(names)+len(ids), func(i int) {
+		if i < len(names) {
+			if _, err := packages.get(ctx, names[i]); err != nil {
+				log.Printf("Outdated check skipped: %v", err)
+			}
+			return
+		}
+		advisories.severity(ctx, ids[i-len(names)])
+	})
+	projects, requested, projectErr := s.loadProjects(ctx, keys)
+	var versionFallback *domain.LookupError[domain.PackageRef]
+	var projectFallback *domain.LookupError[string]
+	made := lookups{
+		projects: requested,
+		versionFallback: errors.As(versionErr, &versionFallback),
+		projectFallback: errors.As(projectErr, &projectFallback),
+	}
 
 	for i := range nodes {
+		if upstream, err := packages.get(ctx, nodes[i].Name); err == nil {
+			nodes[i].LatestVersion = upstream.DefaultVersion
+			nodes[i].Behind = versionLag(nodes[i].Version, upstream.DefaultVersion)
+			if !upstream.LatestReleaseAt.IsZero() {
+				nodes[i].LatestReleaseAt = &upstream.LatestReleaseAt
+			}
+		}
+		info, ok := versions[refs[i]]
+		switch {
+		case ok:
+		case lookupFailure(versionErr, refs[i]) != nil:
+			nodes[i].Status, nodes[i].StatusMessage = domain.EnrichmentFetchError, lookupFailure(versionErr, refs[i]).Error()
+			continue
+		default:
+			nodes[i].Status, nodes[i].StatusMessage = domain.EnrichmentNotFound, "Version not found on deps.dev"
+			continue
+		}
+		nodes[i].Advisories = info.Advisories
+		nodes[i].License = strings.Join(info.Licenses, " AND ")
+		nodes[i].Deprecated = info.Deprecated
+		nodes[i].DeprecationReason = info.DeprecationReason
+		if !info.PublishedAt.IsZero() {
+			nodes[i].PublishedAt = &info.PublishedAt
+		}
+		nodes[i].Severity = advisories.maxSeverity(ctx, info.Advisories)
+		nodes[i].ProjectKey = info.ProjectKey
+		if info.ProjectKey == "" {
+			nodes[i].Status = domain.EnrichmentNoProject
+			continue
+		}
+
+		project, ok := projects[info.ProjectKey]
+		switch {
+		case !ok && lookupFailure(projectErr, info.ProjectKey) != nil:
+			nodes[i].Status, nodes[i].StatusMessage = domain.EnrichmentFetchError, lookupFailure(projectErr, info.ProjectKey).Error()
+		case !ok:
+			nodes[i].Status, nodes[i].StatusMessage = domain.EnrichmentNoProject, fmt.Sprintf("Project %s not found on deps.dev", info.ProjectKey)
+		case !project.HasScorecard():
+			nodes[i].Project = project
+			nodes[i].Status = domain.EnrichmentNoScorecard
+		default:
+			nodes[i].Project = project
+			nodes[i].Score = &project.Scorecard.Score
+			nodes[i].Status = domain.EnrichmentScored
+		}
+	}
+	return advisories.fetched(), made
+}
+
+func lookupFailure[K comparable](err error, key K) error {
+	var lookup *domain.LookupError[K]
+	if errors.As(err, &lookup) {
+		return lookup.Failed[key]
+	}
+	return err
+}
+
+func appendUnique(values []string, seen map[string]bool, value string) []string {
+	if seen[value] {
+		return values
+	}
+	seen[value] = true
+	return append(values, value)
+}
+
+func parallel(n int, work func(i int)) {
+	guard := make(chan struct{}, workerLimit)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := range n {
 		guard <- struct{}{}
-		go func(i int) {
+		go func() {
 			defer func() {
 				wg.Done()
 				<-guard
 			}()
-
-			ref := domain.PackageRef{
-				Name: nodes[i].Name,
-				Version: nodes[i].Version,
-			}
-			projectKey, err := s.client.FetchProjectKey(ctx, ref)
-			if err != nil || projectKey == "" {
-				return
-			}
-
-			score, err := s.client.FetchScore(ctx, projectKey)
-			if err != nil {
-				return
-			}
-
-			nodes[i].Score = &score
-		}(i)
+			work(i)
+		}()
 	}
 	wg.Wait()
-}
\ No newline at end of file
+}
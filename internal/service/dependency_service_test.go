@@ -0,0 +1,478 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/depsdev/depsdevtest"
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+type fakeClient struct {
+	mu sync.Mutex
+	version string
+	scores map[string]float64
+	advisories map[string][]string
+	failing map[string]bool
+	missing map[string]bool
+	versionCalls int
+	projectCalls int
+}
+
+func (c *fakeClient) FetchDefaultVersion(ctx context.Context, name string) (string, error) {
+	return c.version, nil
+}
+
+func (c *fakeClient) FetchPackage(ctx context.Context, name string) (domain.PackageInfo, error) {
+	return domain.PackageInfo{DefaultVersion: "1.0.0"}, nil
+}
+
+func (c *fakeClient) FetchDependencies(ctx context.Context, ref domain.PackageRef) ([]domain.DependencyNode, []domain.Edge, error) {
+	nodes := []domain.DependencyNode{{Name: ref.Name, Version: ref.Version, Relation: "SELF"}}
+	var edges []domain.Edge
+	for _, name := range slices.Sorted(maps.Keys(c.scores)) {
+		dep := domain.PackageRef{Name: name, Version: "1.0.0"}
+		nodes = append(nodes, domain.DependencyNode{Name: name, Version: dep.Version, Relation: "DIRECT", Depth: 1})
+		edges = append(edges, domain.Edge{From: ref, To: dep})
+	}
+	return nodes, edges, nil
+}
+
+func (c *fakeClient) FetchVersion(ctx context.Context, ref domain.PackageRef) (domain.VersionInfo, error) {
+	c.mu.Lock()
+	c.versionCalls++
+	c.mu.Unlock()
+	if c.failing[ref.Name] {
+		return domain.VersionInfo{}, errors.New("Error from deps.dev: 503")
+	}
+	if c.missing[ref.Name] {
+		return domain.VersionInfo{}, domain.Errorf(domain.KindUpstreamNotFound, "Error from deps.dev: 404")
+	}
+	info := domain.VersionInfo{Advisories: c.advisories[ref.Name], Licenses: []string{"MIT"}}
+	if _, ok := c.scores[ref.Name]; ok {
+		info.ProjectKey = "github.com/example/" + ref.Name
+	}
+	return info, nil
+}
+
+func (c *fakeClient) FetchVersions(ctx context.Context, refs []domain.PackageRef) (map[domain.PackageRef]domain.VersionInfo, error) {
+	versions := make(map[domain.PackageRef]domain.VersionInfo, len(refs))
+	failed := make(map[domain.PackageRef]error)
+	for _, ref := range refs {
+		info, err := c.FetchVersion(ctx, ref)
+		if domain.KindOf(err) == domain.KindUpstreamNotFound {
+			continue
+		}
+		if err != nil {
+			failed[ref] = err
+			continue
+		}
+		versions[ref] = info
+	}
+	if len(failed) > 0 {
+		return versions, &domain.LookupError[domain.PackageRef]{Failed: failed}
+	}
+	return versions, nil
+}
+
+func (c *fakeClient) FetchAdvisory(ctx context.Context, id string) (domain.Advisory, error) {
+	return domain.Advisory{ID: id, Title: "Advisory " + id, CVSS3Score: 7.5}, nil
+}
+
+func (c *fakeClient) FetchProject(ctx context.Context, key string) (domain.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.projectCalls++
+	name := key[len("github.com/example/"):]
+	now := time.Now().UTC()
+	return domain.Project{Key: key, Scorecard: domain.Scorecard{Score: c.scores[name], Date: now, Checks: []domain.ScorecardCheck{
+		{Name: "Maintained", Score: 10},
+	}}, FetchedAt: now}, nil
+}
+
+func (c *fakeClient) FetchProjects(ctx context.Context, keys []string) (map[string]domain.Project, error) {
+	projects := make(map[string]domain.Project, len(keys))
+	for _, key := range keys {
+		projects[key], _ = c.FetchProject(ctx, key)
+	}
+	return projects, nil
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, events []domain.Event) {
+	p.events = append(p.events, events...)
+}
+
+func newTestService(client *fakeClient, cfg Config) (*DependencyService, *recordingPublisher) {
+	repo := memory.NewRepository()
+	events := &recordingPublisher{}
+	return NewDependencyService(repo, repo, repo, client, events, cfg), events
+}
+
+func TestStoreDependencies(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{
+		version: "1.0.0",
+		scores: map[string]float64{"express": 8, "qs": 6},
+		advisories: map[string][]string{"qs": {"GHSA-1"}},
+	}
+	service, events := newTestService(client, Config{AlertScoreThreshold: 4})
+
+	if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+
+	pkg, err := service.GetDependencies(ctx, "app", domain.NodeQuery{
+		Filters: []domain.Filter{{Column: "score", Operator: domain.FilterGte, Value: "7"}},
+	})
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(pkg.Dependencies) != 1 || pkg.Dependencies[0].Name != "express" {
+		t.Fatalf("Got %+v, expected only express", pkg.Dependencies)
+	}
+
+	pkg, err = service.GetDependencies(ctx, "app", domain.NodeQuery{
+		Filters: []domain.Filter{{Column: "name", Operator: domain.FilterEq, Value: "qs"}},
+	})
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	qs := pkg.Dependencies[0]
+	if qs.Score == nil || *qs.Score != 6 || qs.Severity == nil || *qs.Severity != 7.5 || qs.License != "MIT" {
+		t.Errorf("Got qs %+v, expected score 6, severity 7.5 and MIT license", qs)
+	}
+	if len(events.events) != 1 || events.events[0].Type != domain.EventPackageRefreshed {
+		t.Errorf("Got events %+v, expected a single refresh", events.events)
+	}
+
+	detail, err := service.GetDependencyDetail(ctx, "app", "qs")
+	if err != nil {
+		t.Fatalf("GetDependencyDetail failed: %v", err)
+	}
+	if len(detail.Advisories) != 1 || detail.Advisories[0].Title != "Advisory GHSA-1" {
+		t.Errorf("Got advisories %+v, expected stored GHSA-1 title", detail.Advisories)
+	}
+	if detail.Scorecard == nil || len(detail.Scorecard.Checks) != 1 {
+		t.Errorf("Got scorecard %+v, expected stored checks", detail.Scorecard)
+	}
+
+	client.version = "2.0.0"
+	client.scores["express"] = 2
+	if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+
+	alerts, err := service.ListAlerts(ctx, false)
+	if err != nil {
+		t.Fatalf("ListAlerts failed: %v", err)
+	}
+	kinds := make(map[domain.AlertKind]bool)
+	for _, alert := range alerts {
+		kinds[alert.Kind] = true
+	}
+	if len(alerts) != 2 || !kinds[domain.AlertVersionChanged] || !kinds[domain.AlertScoreDropped] {
+		t.Errorf("Got alerts %+v, expected version change and score drop", alerts)
+	}
+
+	snapshots, err := service.ListHistory(ctx, "app", 10)
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("Got %d snapshots, expected 2", len(snapshots))
+	}
+}
+
+func TestStoreDependenciesReusesProjects(t *testing.T) {
+	tests := []struct {
+		name string
+		maxAge time.Duration
+		expected int
+	}{
+		{name: "disabled", maxAge: 0, expected: 4},
+		{name: "fresh", maxAge: time.Hour, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &fakeClient{version: "1.0.0", scores: map[string]float64{"express": 8, "qs": 6}}
+			service, _ := newTestService(client, Config{ProjectMaxAge: tt.maxAge})
+
+			for _, name := range []string{"app", "other"} {
+				if _, err := service.StoreDependencies(ctx, name); err != nil {
+					t.Fatalf("StoreDependencies %s failed: %v", name, err)
+				}
+			}
+			if client.projectCalls != tt.expected {
+				t.Errorf("Got %d project fetches, expected %d", client.projectCalls, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStoreDependenciesIncremental(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{
+		version: "1.0.0",
+		scores: map[string]float64{"express": 8, "qs": 6},
+		advisories: map[string][]string{"qs": {"GHSA-1"}},
+	}
+	service, _ := newTestService(client, Config{ProjectMaxAge: time.Hour})
+
+	if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+	client.scores["debug"] = 5
+	client.versionCalls = 0
+	pkg, err := service.StoreDependencies(ctx, "app")
+	if err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+
+	expected := domain.RefreshStats{Reused: 3, Enriched: 1, CallsSaved: 4}
+	if *pkg.Refresh != expected {
+		t.Errorf("Got refresh stats %+v, expected %+v", *pkg.Refresh, expected)
+	}
+	if client.versionCalls != 1 {
+		t.Errorf("Got %d version fetches, expected 1", client.versionCalls)
+	}
+
+	stored, err := service.GetDependencies(ctx, "app", domain.NodeQuery{
+		Filters: []domain.Filter{{Column: "name", Operator: domain.FilterEq, Value: "qs"}},
+	})
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	qs := stored.Dependencies[0]
+	if qs.Score == nil || *qs.Score != 6 || len(qs.Advisories) != 1 || qs.Severity == nil {
+		t.Errorf("Got qs %+v, expected reused score and advisories", qs)
+	}
+}
+
+func TestStoreDependenciesPrunesSnapshots(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{version: "1.0.0", scores: map[string]float64{"express": 8, "qs": 6}}
+	service, _ := newTestService(client, Config{SnapshotRetention: 2})
+
+	for range 3 {
+		if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+			t.Fatalf("StoreDependencies failed: %v", err)
+		}
+	}
+	snapshots, err := service.ListHistory(ctx, "app", 10)
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("Got %d snapshots, expected retention of 2", len(snapshots))
+	}
+}
+
+func TestRetryFailed(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{
+		version: "1.0.0",
+		scores: map[string]float64{"express": 8, "qs": 6, "unpublished": 5},
+		failing: map[string]bool{"qs": true},
+		missing: map[string]bool{"unpublished": true},
+	}
+	service, _ := newTestService(client, Config{})
+
+	if _, err := service.RetryFailed(ctx, "app"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Got %v, expected ErrNotFound", err)
+	}
+	pkg, err := service.StoreDependencies(ctx, "app")
+	if err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+	statuses := make(map[string]domain.EnrichmentStatus)
+	for _, node := range pkg.Dependencies {
+		statuses[node.Name] = node.Status
+		if node.Name == "qs" && node.StatusMessage != "Error from deps.dev: 503" {
+			t.Errorf("Got status message %q, expected the failure of qs only", node.StatusMessage)
+		}
+	}
+	if statuses["app"] != domain.EnrichmentNoProject || statuses["express"] != domain.EnrichmentScored || statuses["qs"] != domain.EnrichmentFetchError {
+		t.Fatalf("Got statuses %v, expected qs to fail", statuses)
+	}
+	if statuses["unpublished"] != domain.EnrichmentNotFound {
+		t.Fatalf("Got statuses %v, expected unpublished to be not found", statuses)
+	}
+
+	delete(client.failing, "qs")
+	client.versionCalls = 0
+	pkg, err = service.RetryFailed(ctx, "app")
+	if err != nil {
+		t.Fatalf("RetryFailed failed: %v", err)
+	}
+	expected := domain.RefreshStats{Reused: 3, Enriched: 1}
+	if *pkg.Refresh != expected || client.versionCalls != 1 {
+		t.Errorf("Got refresh stats %+v with %d version fetches, expected %+v with 1", *pkg.Refresh, client.versionCalls, expected)
+	}
+
+	stored, err := service.GetDependencies(ctx, "app", domain.NodeQuery{
+		Filters: []domain.Filter{{Column: "status", Operator: domain.FilterEq, Value: "scored"}},
+	})
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(stored.Dependencies) != 2 {
+		t.Errorf("Got %+v, expected express and qs scored", stored.Dependencies)
+	}
+	if edges, err := service.repo.ListEdges(ctx, "app"); err != nil || len(edges) != 3 {
+		t.Errorf("Got %d edges (%v), expected edges kept after retry", len(edges), err)
+	}
+}
+
+func TestDeleteDependenciesByName(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{version: "1.0.0", scores: map[string]float64{"express": 8}}
+	service, events := newTestService(client, Config{})
+
+	if err := service.DeleteDependenciesByName(ctx, "app"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Got %v, expected ErrNotFound", err)
+	}
+	if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+	if err := service.DeleteDependenciesByName(ctx, "app"); err != nil {
+		t.Fatalf("DeleteDependenciesByName failed: %v", err)
+	}
+	if _, err := service.GetDependencies(ctx, "app", domain.NodeQuery{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Got %v, expected ErrNotFound after delete", err)
+	}
+	if last := events.events[len(events.events)-1]; last.Type != domain.EventPackageDeleted {
+		t.Errorf("Got last event %s, expected %s", last.Type, domain.EventPackageDeleted)
+	}
+}
+
+type blockingClient struct {
+	*fakeClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingClient) FetchDependencies(ctx context.Context, ref domain.PackageRef) ([]domain.DependencyNode, []domain.Edge, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return c.fakeClient.FetchDependencies(ctx, ref)
+}
+
+func TestStoreDependenciesCoalesces(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := context.Background()
+		client := &blockingClient{
+			fakeClient: &fakeClient{version: "1.0.0", scores: map[string]float64{"express": 8}},
+			started: make(chan struct{}, 10),
+			release: make(chan struct{}),
+		}
+		repo := memory.NewRepository()
+		service := NewDependencyService(repo, repo, repo, client, &recordingPublisher{}, Config{})
+
+		const callers = 5
+		results := make(chan *domain.Package, callers)
+		for range callers {
+			go func() {
+				pkg, err := service.StoreDependencies(ctx, "app")
+				if err != nil {
+					t.Errorf("StoreDependencies failed: %v", err)
+				}
+				results <- pkg
+			}()
+		}
+		synctest.Wait()
+		if len(client.started) != 1 {
+			t.Fatalf("Got %d dependency fetches, expected callers to wait for one refresh", len(client.started))
+		}
+		<-client.started
+		if _, err := service.RetryFailed(ctx, "app"); domain.KindOf(err) != domain.KindConflict {
+			t.Errorf("Got %v, expected conflict while refreshing", err)
+		}
+		deleted := make(chan error)
+		go func() { deleted <- service.DeleteDependenciesByName(ctx, "app") }()
+		synctest.Wait()
+		close(client.release)
+
+		first := <-results
+		for range callers - 1 {
+			if pkg := <-results; pkg != first {
+				t.Errorf("Got separate results, expected callers to share one refresh")
+			}
+		}
+		if len(client.started) != 0 {
+			t.Errorf("Got %d extra dependency fetches, expected 1 in total", len(client.started))
+		}
+		if err := <-deleted; err != nil {
+			t.Errorf("Got %v, expected delete to wait for the refresh", err)
+		}
+	})
+}
+
+type perNodeClient struct {
+	outbound.DepsDevClient
+}
+
+func (c perNodeClient) FetchVersions(ctx context.Context, refs []domain.PackageRef) (map[domain.PackageRef]domain.VersionInfo, error) {
+	versions := make(map[domain.PackageRef]domain.VersionInfo, len(refs))
+	for _, ref := range refs {
+		if info, err := c.FetchVersion(ctx, ref); err == nil {
+			versions[ref] = info
+		}
+	}
+	return versions, nil
+}
+
+func (c perNodeClient) FetchProjects(ctx context.Context, keys []string) (map[string]domain.Project, error) {
+	projects := make(map[string]domain.Project, len(keys))
+	for _, key := range keys {
+		if project, err := c.FetchProject(ctx, key); err == nil {
+			projects[key] = project
+		}
+	}
+	return projects, nil
+}
+
+func BenchmarkEnrichment(b *testing.B) {
+	server := depsdevtest.NewServer(1000)
+	defer server.Close()
+	client := depsdev.NewClient(server.Client())
+
+	tests := []struct {
+		name string
+		client outbound.DepsDevClient
+	}{
+		{name: "per-node", client: perNodeClient{client}},
+		{name: "batch", client: client},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			repo := memory.NewRepository()
+			service := NewDependencyService(repo, repo, repo, tt.client, &recordingPublisher{}, Config{})
+			ctx := context.Background()
+			server.Reset()
+
+			for b.Loop() {
+				if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+					b.Fatalf("StoreDependencies failed: %v", err)
+				}
+			}
+			b.ReportMetric(float64(server.Total())/float64(b.N), "requests/op")
+			b.ReportMetric(float64(server.Requests("version")+server.Requests("versionbatch"))/float64(b.N), "version-requests/op")
+			b.ReportMetric(float64(server.Requests("project")+server.Requests("projectbatch"))/float64(b.N), "project-requests/op")
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+const maxPaths = 25
+
+func (s *DependencyService) GetDependencyDetail(ctx context.Context, name string, dependency string) (*domain.DependencyDetail, error) {
+	pkg, err := s.repo.GetByName(ctx, name, domain.NodeQuery{
+		Filters: []domain.Filter{{Column: "name", Operator: domain.FilterEq, Value: dependency}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Dependencies) == 0 {
+		return nil, domain.ErrDependencyNotFound
+	}
+
+	edges, err := s.repo.ListEdges(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.ListScoreHistory(ctx, name, dependency)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := s.repo.FindDependents(ctx, dependency, "")
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.DependencyDetail{
+		Package: pkg.PackageRef,
+		Name: dependency,
+		Versions: pkg.Dependencies,
+		Paths: findPaths(pkg.PackageRef, edges, dependency, maxPaths),
+		ScoreHistory: history,
+	}
+	for _, dependent := range dependents {
+		if dependent.Package.Name != name {
+			detail.UsedBy = append(detail.UsedBy, dependent)
+		}
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, node := range pkg.Dependencies {
+		for _, id := range node.Advisories {
+			ids = appendUnique(ids, seen, id)
+		}
+		if detail.SourceURL == "" && node.ProjectKey != "" {
+			detail.SourceURL = domain.SourceURL(node.ProjectKey)
+			detail.Project = node.Project
+		}
+	}
+	advisories, err := s.repo.GetAdvisories(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		advisory, ok := advisories[id]
+		if !ok {
+			advisory = domain.Advisory{ID: id}
+		}
+		detail.Advisories = append(detail.Advisories, advisory)
+	}
+	if detail.Project != nil {
+		projects, err := s.repo.GetProjects(ctx, []string{detail.Project.Key})
+		if err != nil {
+			return nil, err
+		}
+		if project, ok := projects[detail.Project.Key]; ok {
+			detail.Project = &project
+			if project.HasScorecard() {
+				detail.Scorecard = &project.Scorecard
+			}
+		}
+	}
+	return detail, nil
+}
+
+func (s *DependencyService) FindDependents(ctx context.Context, dependency string, version string) ([]domain.Dependent, error) {
+	return s.repo.FindDependents(ctx, dependency, version)
+}
+
+func findPaths(root domain.PackageRef, edges []domain.Edge, target string, limit int) [][]domain.PackageRef {
+	children := make(map[domain.PackageRef][]domain.PackageRef)
+	parents := make(map[domain.PackageRef][]domain.PackageRef)
+	var queue []domain.PackageRef
+	for _, edge := range edges {
+		children[edge.From] = append(children[edge.From], edge.To)
+		parents[edge.To] = append(parents[edge.To], edge.From)
+		if edge.To.Name == target {
+			queue = append(queue, edge.To)
+		}
+	}
+	if root.Name == target {
+		queue = append(queue, root)
+	}
+
+	reaches := make(map[domain.PackageRef]bool)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if reaches[current] {
+			continue
+		}
+		reaches[current] = true
+		queue = append(queue, parents[current]...)
+	}
+
+	var paths [][]domain.PackageRef
+	visiting := make(map[domain.PackageRef]bool)
+	var walk func(path []domain.PackageRef)
+	walk = func(path []domain.PackageRef) {
+		current := path[len(path)-1]
+		if current.Name == target {
+			paths = append(paths, append([]domain.PackageRef(nil), path...))
+			return
+		}
+		visiting[current] = true
+		for _, child := range children[current] {
+			if len(paths) >= limit {
+				break
+			}
+			if reaches[child] && !visiting[child] {
+				walk(append(path, child))
+			}
+		}
+		visiting[current] = false
+	}
+	if reaches[root] && limit > 0 {
+		walk([]domain.PackageRef{root})
+	}
+	return paths
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestFindPaths(t *testing.T) {
+	root := domain.PackageRef{Name: "root", Version: "1.0.0"}
+	a := domain.PackageRef{Name: "a", Version: "1.0.0"}
+	b := domain.PackageRef{Name: "b", Version: "1.0.0"}
+	qs := domain.PackageRef{Name: "qs", Version: "6.0.0"}
+	qsOld := domain.PackageRef{Name: "qs", Version: "5.0.0"}
+
+	tests := []struct {
+		name string
+		edges []domain.Edge
+		target string
+		limit int
+		expected [][]domain.PackageRef
+	}{
+		{
+			name: "unreachable",
+			edges: []domain.Edge{{From: root, To: a}},
+			target: "qs",
+			limit: 10,
+			expected: nil,
+		},
+		{
+			name: "direct and transitive paths to every version",
+			edges: []domain.Edge{{From: root, To: qs}, {From: root, To: a}, {From: a, To: qsOld}},
+			target: "qs",
+			limit: 10,
+			expected: [][]domain.PackageRef{{root, qs}, {root, a, qsOld}},
+		},
+		{
+			name: "cycle",
+			edges: []domain.Edge{{From: root, To: a}, {From: a, To: b}, {From: b, To: a}, {From: b, To: qs}},
+			target: "qs",
+			limit: 10,
+			expected: [][]domain.PackageRef{{root, a, b, qs}},
+		},
+		{
+			name: "limit",
+			edges: []domain.Edge{{From: root, To: a}, {From: root, To: b}, {From: a, To: qs}, {From: b, To: qs}},
+			target: "qs",
+			limit: 1,
+			expected: [][]domain.PackageRef{{root, a, qs}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findPaths(root, tt.edges, tt.target, tt.limit)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Got paths %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func diamonds(root domain.PackageRef, layers int, bottom domain.PackageRef) []domain.Edge {
+	var edges []domain.Edge
+	previous := []domain.PackageRef{root}
+	for layer := range layers {
+		current := []domain.PackageRef{
+			{Name: fmt.Sprintf("left-%d", layer), Version: "1.0.0"},
+			{Name: fmt.Sprintf("right-%d", layer), Version: "1.0.0"},
+		}
+		for _, from := range previous {
+			for _, to := range current {
+				edges = append(edges, domain.Edge{From: from, To: to})
+			}
+		}
+		previous = current
+	}
+	for _, from := range previous {
+		edges = append(edges, domain.Edge{From: from, To: bottom})
+	}
+	return edges
+}
+
+func TestFindPathsWideDiamonds(t *testing.T) {
+	root := domain.PackageRef{Name: "root", Version: "1.0.0"}
+	qs := domain.PackageRef{Name: "qs", Version: "6.0.0"}
+	side := domain.PackageRef{Name: "side", Version: "1.0.0"}
+
+	tests := []struct {
+		name string
+		edges []domain.Edge
+		target string
+		expected int
+	}{
+		{name: "target below every diamond", edges: diamonds(root, 40, qs), target: "qs", expected: maxPaths},
+		{name: "target outside the diamonds", edges: append(diamonds(root, 40, qs), domain.Edge{From: root, To: side}), target: "side", expected: 1},
+		{name: "unreachable target", edges: diamonds(root, 40, qs), target: "missing", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			paths := findPaths(root, tt.edges, tt.target, maxPaths)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Took %v, expected pruned search", elapsed)
+			}
+			if len(paths) != tt.expected {
+				t.Fatalf("Got %d paths, expected %d", len(paths), tt.expected)
+			}
+			for _, path := range paths {
+				if path[0] != root || path[len(path)-1].Name != tt.target {
+					t.Errorf("Got path %v from %v to %s", path, root, tt.target)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDependencyDetailUsesStoredData(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	takenAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	app := domain.PackageRef{Name: "app", Version: "1.0.0"}
+	qs := domain.PackageRef{Name: "qs", Version: "6.14.0"}
+	project := &domain.Project{
+		Key: "github.com/ljharb/qs",
+		Stars: 8000,
+		Scorecard: domain.Scorecard{Score: 5.5, Date: takenAt, Checks: []domain.ScorecardCheck{
+			{Name: "Maintained", Score: 10, Reason: "30 commits in the last 90 days"},
+		}},
+		FetchedAt: takenAt,
+	}
+	if err := repo.Save(ctx, &domain.Package{
+		PackageRef: app,
+		Dependencies: []domain.DependencyNode{
+			{Name: "app", Version: "1.0.0", Relation: "SELF"},
+			{Name: "qs", Version: "6.14.0", Relation: "DIRECT", Depth: 1, Advisories: []string{"GHSA-1", "GHSA-2"},
+				ProjectKey: project.Key, Project: project, Status: domain.EnrichmentScored},
+		},
+		Edges: []domain.Edge{{From: app, To: qs}},
+		Advisories: []domain.Advisory{{ID: "GHSA-1", Title: "Prototype pollution", CVSS3Score: 7.5}},
+		LastUpdatedAt: takenAt,
+	}, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	service := NewDependencyService(repo, repo, repo, nil, &recordingPublisher{}, Config{})
+	detail, err := service.GetDependencyDetail(ctx, "app", "qs")
+	if err != nil {
+		t.Fatalf("GetDependencyDetail failed: %v", err)
+	}
+
+	expectedAdvisories := []domain.Advisory{{ID: "GHSA-1", Title: "Prototype pollution", CVSS3Score: 7.5}, {ID: "GHSA-2"}}
+	if !reflect.DeepEqual(detail.Advisories, expectedAdvisories) {
+		t.Errorf("Got advisories %+v, expected %+v", detail.Advisories, expectedAdvisories)
+	}
+	if detail.Scorecard == nil || len(detail.Scorecard.Checks) != 1 || detail.Scorecard.Checks[0].Name != "Maintained" {
+		t.Errorf("Got scorecard %+v, expected stored checks", detail.Scorecard)
+	}
+	if detail.Project == nil || detail.Project.Stars != 8000 || detail.SourceURL != "https://github.com/ljharb/qs" {
+		t.Errorf("Got project %+v and source %q", detail.Project, detail.SourceURL)
+	}
+	if !reflect.DeepEqual(detail.Paths, [][]domain.PackageRef{{app, qs}}) {
+		t.Errorf("Got paths %v", detail.Paths)
+	}
+
+	if _, err := service.GetDependencyDetail(ctx, "app", "missing"); err != domain.ErrDependencyNotFound {
+		t.Errorf("Got %v, expected dependency not found", err)
+	}
+}
+
+func TestFindDependents(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	for _, pkg := range []*domain.Package{
+		{
+			PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+			Dependencies: []domain.DependencyNode{
+				{Name: "app", Version: "1.0.0", Relation: "SELF"},
+				{Name: "qs", Version: "6.14.0", Relation: "DIRECT", Depth: 1},
+			},
+		},
+		{
+			PackageRef: domain.PackageRef{Name: "api", Version: "2.0.0"},
+			Dependencies: []domain.DependencyNode{
+				{Name: "api", Version: "2.0.0", Relation: "SELF"},
+				{Name: "express", Version: "5.2.1", Relation: "DIRECT", Depth: 1},
+				{Name: "qs", Version: "5.0.0", Relation: "INDIRECT", Depth: 2},
+			},
+		},
+	} {
+		if err := repo.Save(ctx, pkg, nil); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	service := NewDependencyService(repo, repo, repo, nil, &recordingPublisher{}, Config{})
+
+	tests := []struct {
+		name string
+		dependency string
+		version string
+		expected []string
+	}{
+		{name: "tracked packages", dependency: "qs", expected: []string{"api@2.0.0 qs@5.0.0", "app@1.0.0 qs@6.14.0"}},
+		{name: "version", dependency: "qs", version: "6.14.0", expected: []string{"app@1.0.0 qs@6.14.0"}},
+		{name: "single package", dependency: "express", expected: []string{"api@2.0.0 express@5.2.1"}},
+		{name: "missing dependency", dependency: "lodash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dependents, err := service.FindDependents(ctx, tt.dependency, tt.version)
+			if err != nil {
+				t.Fatalf("FindDependents failed: %v", err)
+			}
+			var got []string
+			for _, dependent := range dependents {
+				got = append(got, fmt.Sprintf("%s@%s %s@%s", dependent.Package.Name, dependent.Package.Version, tt.dependency, dependent.Version))
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
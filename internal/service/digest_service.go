@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+type DigestConfig struct {
+	Period time.Duration
+}
+
+type DigestService struct {
+	repo outbound.Repository
+	alerts outbound.AlertRepository
+	digests outbound.DigestRepository
+	sender outbound.DigestSender
+	config DigestConfig
+}
+
+func NewDigestService(repo outbound.Repository, alerts outbound.AlertRepository, digests outbound.DigestRepository, sender outbound.DigestSender, cfg DigestConfig) *DigestService {
+	return &DigestService{repo: repo, alerts: alerts, digests: digests, sender: sender, config: cfg}
+}
+
+func (s *DigestService) SendDigest(ctx context.Context) error {
+	now := time.Now().UTC()
+	digest, err := s.BuildDigest(ctx, now)
+	if err != nil {
+		return err
+	}
+	if err := s.sender.SendDigest(ctx, digest); err != nil {
+		return fmt.Errorf("Sending digest error: %w", err)
+	}
+	return s.digests.SaveDigestSent(ctx, now)
+}
+
+func (s *DigestService) NextDigest(ctx context.Context) (time.Time, error) {
+	sentAt, err := s.digests.LastDigestSent(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if sentAt == nil {
+		return time.Now().UTC(), nil
+	}
+	return sentAt.Add(s.config.Period), nil
+}
+
+func (s *DigestService) BuildDigest(ctx context.Context, to time.Time) (*domain.Digest, error) {
+	digest := &domain.Digest{
+		From: to.Add(-s.config.Period),
+		To: to,
+	}
+
+	packages, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		digest.Packages = append(digest.Packages, summarize(pkg))
+	}
+
+	alerts, err := s.alerts.ListAlerts(ctx, true, digest.From)
+	if err != nil {
+		return nil, err
+	}
+	for _, alert := range alerts {
+		if alert.CreatedAt.After(digest.To) {
+			continue
+		}
+		switch alert.Kind {
+		case domain.AlertVersionChanged:
+			digest.VersionChanges = append(digest.VersionChanges, alert)
+		case domain.AlertScoreDropped, domain.AlertNewLowScore:
+			digest.ScoreChanges = append(digest.ScoreChanges, alert)
+		case domain.AlertNewAdvisory:
+			digest.NewAdvisories = append(digest.NewAdvisories, alert)
+		}
+	}
+	return digest, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestBuildDigest(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	to := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	pkg := &domain.Package{
+		PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "express", Version: "5.2.1", Relation: "DIRECT", ProjectKey: "github.com/expressjs/express", Project: &domain.Project{
+				Key: "github.com/expressjs/express",
+				Scorecard: domain.Scorecard{Score: 8, Date: to},
+			}},
+			{Name: "qs", Version: "6.14.0", Relation: "INDIRECT"},
+		},
+		LastUpdatedAt: to,
+	}
+	if err := repo.Save(ctx, pkg, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	alerts := []domain.Alert{
+		{PackageName: "app", Kind: domain.AlertVersionChanged, Message: "version", CreatedAt: to.Add(-time.Hour)},
+		{PackageName: "app", Kind: domain.AlertScoreDropped, Message: "dropped", CreatedAt: to.Add(-24 * time.Hour)},
+		{PackageName: "app", Kind: domain.AlertNewLowScore, Message: "low", CreatedAt: to.Add(-48 * time.Hour)},
+		{PackageName: "app", Kind: domain.AlertNewAdvisory, Message: "advisory", CreatedAt: to.Add(-7 * 24 * time.Hour)},
+		{PackageName: "app", Kind: domain.AlertNewAdvisory, Message: "too old", CreatedAt: to.Add(-8 * 24 * time.Hour)},
+		{PackageName: "app", Kind: domain.AlertVersionChanged, Message: "too new", CreatedAt: to.Add(time.Hour)},
+	}
+	if err := repo.SaveAlerts(ctx, alerts); err != nil {
+		t.Fatalf("SaveAlerts failed: %v", err)
+	}
+	if err := repo.AcknowledgeAlert(ctx, alerts[1].ID); err != nil {
+		t.Fatalf("AcknowledgeAlert failed: %v", err)
+	}
+
+	service := NewDigestService(repo, repo, repo, nil, DigestConfig{Period: 7 * 24 * time.Hour})
+	digest, err := service.BuildDigest(ctx, to)
+	if err != nil {
+		t.Fatalf("BuildDigest failed: %v", err)
+	}
+
+	if !digest.From.Equal(to.Add(-7*24*time.Hour)) || !digest.To.Equal(to) {
+		t.Errorf("Got window %v - %v", digest.From, digest.To)
+	}
+	if len(digest.Packages) != 1 {
+		t.Fatalf("Got %d packages, expected 1", len(digest.Packages))
+	}
+	summary := digest.Packages[0]
+	if summary.Dependencies != 2 || summary.Scored != 1 || len(summary.Unscored) != 1 || summary.Unscored[0].Name != "qs" {
+		t.Errorf("Got summary %+v, expected one scored and one unscored dependency", summary)
+	}
+
+	tests := []struct {
+		name string
+		alerts []domain.Alert
+		expected []string
+	}{
+		{name: "version changes", alerts: digest.VersionChanges, expected: []string{"version"}},
+		{name: "score changes", alerts: digest.ScoreChanges, expected: []string{"dropped", "low"}},
+		{name: "new advisories", alerts: digest.NewAdvisories, expected: []string{"advisory"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, alert := range tt.alerts {
+				messages = append(messages, alert.Message)
+			}
+			if len(messages) != len(tt.expected) {
+				t.Fatalf("Got %v, expected %v", messages, tt.expected)
+			}
+			for i := range messages {
+				if messages[i] != tt.expected[i] {
+					t.Errorf("Got %v, expected %v", messages, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+type recordingSender struct {
+	digests []*domain.Digest
+}
+
+func (s *recordingSender) SendDigest(ctx context.Context, digest *domain.Digest) error {
+	s.digests = append(s.digests, digest)
+	return nil
+}
+
+func TestNextDigest(t *testing.T) {
+	ctx := context.Background()
+	period := 24 * time.Hour
+	sentAt := time.Date(2026, 1, 8, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		sentAt *time.Time
+		expected time.Time
+	}{
+		{name: "never sent", expected: time.Time{}},
+		{name: "sent before", sentAt: &sentAt, expected: sentAt.Add(period)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewRepository()
+			if tt.sentAt != nil {
+				if err := repo.SaveDigestSent(ctx, *tt.sentAt); err != nil {
+					t.Fatalf("SaveDigestSent failed: %v", err)
+				}
+			}
+			service := NewDigestService(repo, repo, repo, nil, DigestConfig{Period: period})
+			before := time.Now()
+			next, err := service.NextDigest(ctx)
+			if err != nil {
+				t.Fatalf("NextDigest failed: %v", err)
+			}
+			if tt.expected.IsZero() {
+				if next.Before(before) || next.After(time.Now()) {
+					t.Errorf("Got next digest at %v, expected right away", next)
+				}
+				return
+			}
+			if !next.Equal(tt.expected) {
+				t.Errorf("Got next digest at %v, expected %v", next, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSendDigestRecordsTime(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	sender := &recordingSender{}
+	service := NewDigestService(repo, repo, repo, sender, DigestConfig{Period: 24 * time.Hour})
+
+	if err := service.SendDigest(ctx); err != nil {
+		t.Fatalf("SendDigest failed: %v", err)
+	}
+	if len(sender.digests) != 1 {
+		t.Fatalf("Got %d digests, expected 1", len(sender.digests))
+	}
+	sentAt, err := repo.LastDigestSent(ctx)
+	if err != nil {
+		t.Fatalf("LastDigestSent failed: %v", err)
+	}
+	if sentAt == nil || !sentAt.Equal(sender.digests[0].To) {
+		t.Errorf("Got last digest %v, expected %v", sentAt, sender.digests[0].To)
+	}
+	next, err := service.NextDigest(ctx)
+	if err != nil {
+		t.Fatalf("NextDigest failed: %v", err)
+	}
+	if !next.Equal(sentAt.Add(24 * time.Hour)) {
+		t.Errorf("Got next digest at %v, expected a period after the last one", next)
+	}
+}
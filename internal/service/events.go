@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func refreshEvents(oldPkg, newPkg *domain.Package) []domain.Event {
+	ref := newPkg.PackageRef
+	now := newPkg.LastUpdatedAt
+	events := []domain.Event{{
+		Type: domain.EventPackageRefreshed,
+		Package: ref,
+		Message: fmt.Sprintf("%s %s refreshed with %d dependencies", ref.Name, ref.Version, len(newPkg.Dependencies)),
+		OccurredAt: now,
+	}}
+	if oldPkg == nil {
+		return events
+	}
+
+	if oldPkg.PackageRef.Version != ref.Version {
+		events = append(events, domain.Event{
+			Type: domain.EventVersionChanged,
+			Package: ref,
+			Message: fmt.Sprintf("%s default version changed from %s to %s", ref.Name, oldPkg.PackageRef.Version, ref.Version),
+			OccurredAt: now,
+		})
+	}
+
+	pairs, _ := pairNodes(oldPkg.Dependencies, newPkg.Dependencies)
+	for i, node := range newPkg.Dependencies {
+		if pairs[i] == nil {
+			continue
+		}
+		old := pairs[i].Score
+		if old == nil || node.Score == nil || *node.Score >= *old {
+			continue
+		}
+		events = append(events, domain.Event{
+			Type: domain.EventScoreDropped,
+			Package: ref,
+			Dependency: node.Name,
+			Message: fmt.Sprintf("%s score dropped from %.1f to %.1f in %s", node.Name, *old, *node.Score, ref.Name),
+			OccurredAt: now,
+		})
+	}
+	return events
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"math"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type HealthWeights struct {
+	Relation        map[string]float64 `json:"relation"`
+	DepthDecay      float64            `json:"depth_decay"`
+	FanIn           float64            `json:"fan_in"`
+	AdvisoryPenalty float64            `json:"advisory_penalty"`
+	MissingScore    float64            `json:"missing_score"`
+}
+
+func DefaultHealthWeights() HealthWeights {
+	return HealthWeights{
+		Relation: map[string]float64{
+			"SELF": 2.0,
+			"DIRECT": 1.0,
+			"INDIRECT": 0.5,
+		},
+		DepthDecay: 0.8,
+		FanIn: 0.1,
+		AdvisoryPenalty: 0.5,
+		MissingScore: 3.0,
+	}
+}
+
+func healthScore(pkg *domain.Package, weights HealthWeights) *float64 {
+	var total, weightSum float64
+	advisories := 0
+	for _, node := range pkg.Dependencies {
+		advisories += len(node.Advisories)
+
+		weight, ok := weights.Relation[node.Relation]
+		if !ok {
+			weight = 1.0
+		}
+		if node.Depth > 1 {
+			weight *= math.Pow(weights.DepthDecay, float64(node.Depth-1))
+		}
+		if node.FanIn > 1 {
+			weight *= 1 + weights.FanIn*float64(node.FanIn-1)
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		score := weights.MissingScore
+		if node.Score != nil {
+			score = *node.Score
+		}
+		total += weight * score
+		weightSum += weight
+	}
+	if weightSum == 0 {
+		return nil
+	}
+
+	health := total/weightSum - weights.AdvisoryPenalty*float64(advisories)
+	health = math.Round(math.Max(0, math.Min(10, health))*10) / 10
+	return &health
+}
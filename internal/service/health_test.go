@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestHealthScore(t *testing.T) {
+	weights := HealthWeights{
+		Relation: map[string]float64{"SELF": 2.0, "DIRECT": 1.0, "INDIRECT": 0.5},
+		DepthDecay: 0.5,
+		FanIn: 1.0,
+		AdvisoryPenalty: 0.5,
+		MissingScore: 0.0,
+	}
+	tests := []struct {
+		name string
+		nodes []domain.DependencyNode
+		expected *float64
+	}{
+		{
+			name: "no dependencies",
+			expected: nil,
+		},
+		{
+			name: "relation weights",
+			nodes: []domain.DependencyNode{
+				{Relation: "SELF", Score: score(9.0)},
+				{Relation: "DIRECT", Depth: 1, FanIn: 1, Score: score(6.0)},
+			},
+			expected: score(8.0),
+		},
+		{
+			name: "depth, fan-in and missing score",
+			nodes: []domain.DependencyNode{
+				{Relation: "DIRECT", Depth: 1, FanIn: 1, Score: score(8.0)},
+				{Relation: "INDIRECT", Depth: 3, FanIn: 3},
+			},
+			expected: score(5.8),
+		},
+		{
+			name: "advisory penalty",
+			nodes: []domain.DependencyNode{
+				{Relation: "DIRECT", Depth: 1, FanIn: 1, Score: score(8.0), Advisories: []string{"GHSA-1", "GHSA-2"}},
+			},
+			expected: score(7.0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := healthScore(&domain.Package{Dependencies: tt.nodes}, weights)
+			if (got == nil) != (tt.expected == nil) {
+				t.Fatalf("Got %v, expected %v", got, tt.expected)
+			}
+			if got != nil && *got != *tt.expected {
+				t.Errorf("Got health %.2f, expected %.2f", *got, *tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+type refreshFlight struct {
+	done chan struct{}
+	pkg *domain.Package
+	err error
+}
+
+type refreshGroup struct {
+	mu sync.Mutex
+	flights map[string]*refreshFlight
+}
+
+func newRefreshGroup() *refreshGroup {
+	return &refreshGroup{flights: make(map[string]*refreshFlight)}
+}
+
+func (g *refreshGroup) do(ctx context.Context, name string, refresh func(ctx context.Context) (*domain.Package, error)) (*domain.Package, error) {
+	g.mu.Lock()
+	flight, ok := g.flights[name]
+	if !ok {
+		flight = &refreshFlight{done: make(chan struct{})}
+		g.flights[name] = flight
+		go func() {
+			flight.pkg, flight.err = refresh(context.WithoutCancel(ctx))
+			g.mu.Lock()
+			delete(g.flights, name)
+			g.mu.Unlock()
+			close(flight.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-flight.done:
+		return flight.pkg, flight.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type packageLock struct {
+	sem chan struct{}
+	holders int
+}
+
+type packageLocks struct {
+	mu sync.Mutex
+	locks map[string]*packageLock
+	shared outbound.PackageLocker
+}
+
+func newPackageLocks(shared outbound.PackageLocker) *packageLocks {
+	return &packageLocks{locks: make(map[string]*packageLock), shared: shared}
+}
+
+func (l *packageLocks) lock(ctx context.Context, name string) (func(), error) {
+	unlock, err := l.local(ctx, name, true)
+	if err != nil {
+		return nil, fmt.Errorf("Package lock error: %w", err)
+	}
+	if l.shared == nil {
+		return unlock, nil
+	}
+	unlockShared, err := l.shared.LockPackage(ctx, name)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("Package lock error: %w", err)
+	}
+	return func() {
+		unlockShared()
+		unlock()
+	}, nil
+}
+
+func (l *packageLocks) tryLock(ctx context.Context, name string) (func(), bool, error) {
+	unlock, _ := l.local(ctx, name, false)
+	if unlock == nil {
+		return nil, false, nil
+	}
+	if l.shared == nil {
+		return unlock, true, nil
+	}
+	unlockShared, ok, err := l.shared.TryLockPackage(ctx, name)
+	if err != nil || !ok {
+		unlock()
+		if err != nil {
+			return nil, false, fmt.Errorf("Package lock error: %w", err)
+		}
+		return nil, false, nil
+	}
+	return func() {
+		unlockShared()
+		unlock()
+	}, true, nil
+}
+
+func (l *packageLocks) local(ctx context.Context, name string, wait bool) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &packageLock{sem: make(chan struct{}, 1)}
+		l.locks[name] = lock
+	}
+	lock.holders++
+	l.mu.Unlock()
+
+	release := func() {
+		l.mu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(l.locks, name)
+		}
+		l.mu.Unlock()
+	}
+	if wait {
+		select {
+		case lock.sem <- struct{}{}:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	} else {
+		select {
+		case lock.sem <- struct{}{}:
+		default:
+			release()
+			return nil, nil
+		}
+	}
+	return func() {
+		<-lock.sem
+		release()
+	}, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type lockingRepository struct {
+	*memory.Repository
+	mu sync.Mutex
+	calls []string
+	err error
+}
+
+func (r *lockingRepository) LockPackage(ctx context.Context, name string) (func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		r.calls = append(r.calls, "fail "+name)
+		return nil, r.err
+	}
+	r.calls = append(r.calls, "lock "+name)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, "unlock "+name)
+	}, nil
+}
+
+func (r *lockingRepository) TryLockPackage(ctx context.Context, name string) (func(), bool, error) {
+	unlock, err := r.LockPackage(ctx, name)
+	return unlock, err == nil, err
+}
+
+func TestPackageLocksShared(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		err error
+		expected []string
+	}{
+		{name: "held for the whole write", expected: []string{"lock app", "unlock app", "lock app", "unlock app"}},
+		{name: "failure releases local lock", err: errors.New("connection refused"), expected: []string{"fail app", "fail app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &lockingRepository{Repository: memory.NewRepository(), err: tt.err}
+			service := NewDependencyService(repo, repo, repo, &fakeClient{version: "1.0.0"}, &recordingPublisher{}, Config{})
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for range 2 {
+					_, err := service.StoreDependencies(ctx, "app")
+					if (err != nil) != (tt.err != nil) || (err != nil && !errors.Is(err, tt.err)) {
+						t.Errorf("Got error %v, expected %v", err, tt.err)
+					}
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Second refresh blocked, local lock was not released")
+			}
+			if !slices.Equal(repo.calls, tt.expected) {
+				t.Errorf("Got calls %v, expected %v", repo.calls, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPackageLocksTry(t *testing.T) {
+	ctx := context.Background()
+	locks := newPackageLocks(nil)
+
+	unlock, err := locks.lock(ctx, "app")
+	if err != nil {
+		t.Fatalf("Lock error: %v", err)
+	}
+	if _, ok, err := locks.tryLock(ctx, "app"); ok || err != nil {
+		t.Errorf("Got locked %v (%v), expected try lock to fail while held", ok, err)
+	}
+	other, ok, err := locks.tryLock(ctx, "other")
+	if !ok || err != nil {
+		t.Fatalf("Got locked %v (%v), expected other package to be free", ok, err)
+	}
+	other()
+	unlock()
+
+	unlock, ok, err = locks.tryLock(ctx, "app")
+	if !ok || err != nil {
+		t.Fatalf("Got locked %v (%v), expected try lock after release", ok, err)
+	}
+	unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("Got %d locks left, expected released locks to be removed", len(locks.locks))
+	}
+}
+
+func TestRetryFailedConflicts(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{version: "1.0.0", scores: map[string]float64{"qs": 6}, failing: map[string]bool{"qs": true}}
+	service, _ := newTestService(client, Config{})
+	if _, err := service.StoreDependencies(ctx, "app"); err != nil {
+		t.Fatalf("StoreDependencies failed: %v", err)
+	}
+
+	unlock, err := service.locks.lock(ctx, "app")
+	if err != nil {
+		t.Fatalf("Lock error: %v", err)
+	}
+	if _, err := service.RetryFailed(ctx, "app"); domain.KindOf(err) != domain.KindConflict {
+		t.Errorf("Got %v, expected conflict while the package is locked", err)
+	}
+	unlock()
+	if _, err := service.RetryFailed(ctx, "app"); err != nil {
+		t.Errorf("Got %v, expected retry once the lock is released", err)
+	}
+}
+
+func TestPackageLocksContext(t *testing.T) {
+	ctx := context.Background()
+	locks := newPackageLocks(nil)
+
+	unlock, err := locks.lock(ctx, "app")
+	if err != nil {
+		t.Fatalf("Lock error: %v", err)
+	}
+	waiting, cancel := context.WithCancel(ctx)
+	failed := make(chan error)
+	go func() {
+		_, err := locks.lock(waiting, "app")
+		failed <- err
+	}()
+	cancel()
+	if err := <-failed; !errors.Is(err, context.Canceled) {
+		t.Errorf("Got %v, expected queued lock to give up when its context is canceled", err)
+	}
+	unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("Got %d locks left, expected abandoned waits to be removed", len(locks.locks))
+	}
+}
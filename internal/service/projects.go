@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (s *DependencyService) loadProjects(ctx context.Context, keys []string) (map[string]*domain.Project, int, error) {
+	projects := make(map[string]*domain.Project, len(keys))
+	missing := keys
+	if s.config.ProjectMaxAge > 0 && len(keys) > 0 {
+		if stored, err := s.repo.GetProjects(ctx, keys); err == nil {
+			missing = nil
+			for _, key := range keys {
+				project, ok := stored[key]
+				if !ok || time.Since(project.FetchedAt) > s.config.ProjectMaxAge {
+					missing = append(missing, key)
+					continue
+				}
+				projects[key] = &project
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return projects, 0, nil
+	}
+
+	fetched, err := s.client.FetchProjects(ctx, missing)
+	for key, project := range fetched {
+		projects[key] = &project
+	}
+	return projects, len(missing), err
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+type sharedProjectClient struct {
+	*fakeClient
+	batches [][]string
+	batchMu sync.Mutex
+}
+
+func (c *sharedProjectClient) FetchVersions(ctx context.Context, refs []domain.PackageRef) (map[domain.PackageRef]domain.VersionInfo, error) {
+	versions := make(map[domain.PackageRef]domain.VersionInfo, len(refs))
+	for _, ref := range refs {
+		versions[ref] = domain.VersionInfo{ProjectKey: "github.com/example/" + ref.Name[:1]}
+	}
+	return versions, nil
+}
+
+func (c *sharedProjectClient) FetchProjects(ctx context.Context, keys []string) (map[string]domain.Project, error) {
+	c.batchMu.Lock()
+	c.batches = append(c.batches, slices.Sorted(slices.Values(keys)))
+	c.batchMu.Unlock()
+	return c.fakeClient.FetchProjects(ctx, keys)
+}
+
+func TestEnrichmentDedupesProjects(t *testing.T) {
+	client := &sharedProjectClient{fakeClient: &fakeClient{scores: map[string]float64{"a": 7, "b": 4}}}
+	service := NewDependencyService(memory.NewRepository(), nil, nil, client, &recordingPublisher{}, Config{})
+	nodes := []domain.DependencyNode{
+		{Name: "a-core", Version: "1.0.0"},
+		{Name: "a-utils", Version: "1.0.0"},
+		{Name: "a-core", Version: "2.0.0"},
+		{Name: "b-plugin", Version: "1.0.0"},
+	}
+	service.enrichWithScores(context.Background(), nodes)
+
+	expected := [][]string{{"github.com/example/a", "github.com/example/b"}}
+	if !slices.EqualFunc(client.batches, expected, slices.Equal) || client.projectCalls != 2 {
+		t.Errorf("Got batches %v (%d projects), expected a single batch of 2 distinct keys", client.batches, client.projectCalls)
+	}
+	for _, node := range nodes {
+		want := map[byte]float64{'a': 7, 'b': 4}[node.Name[0]]
+		if node.Project == nil || node.Score == nil || *node.Score != want {
+			t.Errorf("Got %s@%s project %+v, expected score %v", node.Name, node.Version, node.Project, want)
+		}
+	}
+}
+
+func TestLoadProjects(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	fresh := time.Now().UTC()
+	stale := fresh.Add(-2 * time.Hour)
+	stored := func(key string, fetchedAt time.Time) domain.DependencyNode {
+		return domain.DependencyNode{Name: key[len("github.com/example/"):], Version: "1.0.0", ProjectKey: key,
+			Project: &domain.Project{Key: key, Scorecard: domain.Scorecard{Score: 1, Date: fetchedAt}, FetchedAt: fetchedAt}}
+	}
+	if err := repo.Save(ctx, &domain.Package{
+		PackageRef: domain.PackageRef{Name: "app", Version: "1.0.0"},
+		Dependencies: []domain.DependencyNode{
+			{Name: "app", Version: "1.0.0", Relation: "SELF"},
+			stored("github.com/example/fresh", fresh),
+			stored("github.com/example/stale", stale),
+		},
+	}, nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	keys := []string{"github.com/example/fresh", "github.com/example/stale", "github.com/example/new"}
+	tests := []struct {
+		name string
+		maxAge time.Duration
+		fetched []string
+	}{
+		{name: "reuse disabled", maxAge: 0, fetched: keys},
+		{name: "fresh reused", maxAge: time.Hour, fetched: []string{"github.com/example/stale", "github.com/example/new"}},
+		{name: "everything fresh enough", maxAge: 3 * time.Hour, fetched: []string{"github.com/example/new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &sharedProjectClient{fakeClient: &fakeClient{scores: map[string]float64{"fresh": 8, "stale": 5, "new": 3}}}
+			service := NewDependencyService(repo, nil, nil, client, &recordingPublisher{}, Config{ProjectMaxAge: tt.maxAge})
+			projects, requested, err := service.loadProjects(ctx, keys)
+			if err != nil {
+				t.Fatalf("loadProjects failed: %v", err)
+			}
+			if requested != len(tt.fetched) {
+				t.Errorf("Got %d requested projects, expected %d", requested, len(tt.fetched))
+			}
+			if len(projects) != len(keys) {
+				t.Errorf("Got %d projects, expected %d", len(projects), len(keys))
+			}
+			expected := [][]string{slices.Sorted(slices.Values(tt.fetched))}
+			if !slices.EqualFunc(client.batches, expected, slices.Equal) {
+				t.Errorf("Got batches %v, expected %v", client.batches, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+func reusableNodes(previous *domain.Package, maxAge time.Duration, now time.Time) map[domain.PackageRef]domain.DependencyNode {
+	if previous == nil || maxAge <= 0 || now.Sub(previous.LastUpdatedAt) > maxAge {
+		return nil
+	}
+	reusable := make(map[domain.PackageRef]domain.DependencyNode, len(previous.Dependencies))
+	for _, node := range previous.Dependencies {
+		if node.Status == "" || node.Status == domain.EnrichmentFetchError {
+			continue
+		}
+		if node.ProjectKey != "" && (node.Project == nil || now.Sub(node.Project.FetchedAt) > maxAge) {
+			continue
+		}
+		reusable[domain.PackageRef{Name: node.Name, Version: node.Version}] = node
+	}
+	return reusable
+}
+
+func reuseEnrichment(node *domain.DependencyNode, stored domain.DependencyNode) {
+	node.Score = stored.Score
+	node.Advisories = stored.Advisories
+	node.Severity = stored.Severity
+	node.License = stored.License
+	node.ProjectKey = stored.ProjectKey
+	node.Project = stored.Project
+	node.LatestVersion = stored.LatestVersion
+	node.Behind = stored.Behind
+	node.Deprecated = stored.Deprecated
+	node.DeprecationReason = stored.DeprecationReason
+	node.PublishedAt = stored.PublishedAt
+	node.LatestReleaseAt = stored.LatestReleaseAt
+	node.Status = stored.Status
+	node.StatusMessage = stored.StatusMessage
+}
+
+type lookups struct {
+	projects int
+	versionFallback bool
+	projectFallback bool
+}
+
+func batchRequests(keys int) int {
+	return (keys + outbound.BatchLimit - 1) / outbound.BatchLimit
+}
+
+func distinct[K comparable](nodes []domain.DependencyNode, keys func(node domain.DependencyNode) []K) map[K]bool {
+	seen := make(map[K]bool)
+	for _, node := range nodes {
+		for _, key := range keys(node) {
+			seen[key] = true
+		}
+	}
+	return seen
+}
+
+func savedCalls(reused, enriched []domain.DependencyNode, made lookups) int {
+	all := append(slices.Clone(reused), enriched...)
+	refs := func(node domain.DependencyNode) []domain.PackageRef {
+		return []domain.PackageRef{{Name: node.Name, Version: node.Version}}
+	}
+	projects := func(node domain.DependencyNode) []string {
+		if node.ProjectKey == "" {
+			return nil
+		}
+		return []string{node.ProjectKey}
+	}
+	names := func(node domain.DependencyNode) []string { return []string{node.Name} }
+	advisories := func(node domain.DependencyNode) []string { return node.Advisories }
+
+	allRefs, enrichedRefs := len(distinct(all, refs)), len(distinct(enriched, refs))
+	saved := batchRequests(allRefs) - batchRequests(enrichedRefs)
+	if made.versionFallback {
+		saved += allRefs - enrichedRefs
+	}
+	allProjects := len(distinct(all, projects))
+	saved += batchRequests(allProjects) - batchRequests(made.projects)
+	if made.projectFallback {
+		saved += allProjects - made.projects
+	}
+	saved += len(distinct(all, names)) - len(distinct(enriched, names))
+	saved += len(distinct(all, advisories)) - len(distinct(enriched, advisories))
+	return saved
+}
+
+func (s *DependencyService) refreshNodes(ctx context.Context, previous *domain.Package, nodes []domain.DependencyNode) (*domain.RefreshStats, []domain.Advisory) {
+	reusable := reusableNodes(previous, s.config.ProjectMaxAge, time.Now().UTC())
+	var reused, pending []domain.DependencyNode
+	var positions []int
+	for i := range nodes {
+		stored, ok := reusable[domain.PackageRef{Name: nodes[i].Name, Version: nodes[i].Version}]
+		if ok {
+			reuseEnrichment(&nodes[i], stored)
+			reused = append(reused, nodes[i])
+			continue
+		}
+		pending = append(pending, nodes[i])
+		positions = append(positions, i)
+	}
+
+	advisories, made := s.enrichWithScores(ctx, pending)
+	for i, position := range positions {
+		nodes[position] = pending[i]
+	}
+	return &domain.RefreshStats{
+		Reused: len(reused),
+		Enriched: len(pending),
+		CallsSaved: savedCalls(reused, pending, made),
+	}, advisories
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestReusableNodes(t *testing.T) {
+	now := time.Now().UTC()
+	fresh := &domain.Project{Key: "github.com/expressjs/express", FetchedAt: now.Add(-time.Hour)}
+	stale := &domain.Project{Key: "github.com/ljharb/qs", FetchedAt: now.Add(-48 * time.Hour)}
+	previous := &domain.Package{
+		LastUpdatedAt: now.Add(-time.Hour),
+		Dependencies: []domain.DependencyNode{
+			{Name: "express", Version: "5.1.0", ProjectKey: fresh.Key, Project: fresh, Status: domain.EnrichmentScored},
+			{Name: "qs", Version: "6.14.0", ProjectKey: stale.Key, Project: stale, Status: domain.EnrichmentScored},
+			{Name: "debug", Version: "4.4.0", ProjectKey: "github.com/debug-js/debug", Status: domain.EnrichmentNoProject},
+			{Name: "ms", Version: "2.1.3", Status: domain.EnrichmentNoProject},
+			{Name: "left-pad", Version: "1.3.0", Status: domain.EnrichmentFetchError, StatusMessage: "Error from deps.dev: 503"},
+			{Name: "unpublished", Version: "0.0.1", Status: domain.EnrichmentNotFound, StatusMessage: "Version not found on deps.dev"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		previous *domain.Package
+		maxAge time.Duration
+		expected []string
+	}{
+		{name: "first refresh", previous: nil, maxAge: 24 * time.Hour, expected: nil},
+		{name: "reuse disabled", previous: previous, maxAge: 0, expected: nil},
+		{name: "previous refresh expired", previous: previous, maxAge: 30 * time.Minute, expected: nil},
+		{name: "fresh nodes only", previous: previous, maxAge: 24 * time.Hour, expected: []string{"express", "ms", "unpublished"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reusable := reusableNodes(tt.previous, tt.maxAge, now)
+			if len(reusable) != len(tt.expected) {
+				t.Fatalf("Got %d reusable nodes, expected %v", len(reusable), tt.expected)
+			}
+			for _, name := range tt.expected {
+				found := false
+				for ref := range reusable {
+					found = found || ref.Name == name
+				}
+				if !found {
+					t.Errorf("Expected %s to be reusable", name)
+				}
+			}
+		})
+	}
+}
+
+func TestSavedCalls(t *testing.T) {
+	node := func(name, key string, advisories ...string) domain.DependencyNode {
+		return domain.DependencyNode{Name: name, Version: "1.0.0", ProjectKey: key, Advisories: advisories}
+	}
+	express := node("express", "github.com/expressjs/express")
+	qs := node("qs", "github.com/ljharb/qs", "GHSA-1")
+	debug := node("debug", "github.com/debug-js/debug")
+	ms := node("ms", "")
+
+	tests := []struct {
+		name string
+		reused []domain.DependencyNode
+		enriched []domain.DependencyNode
+		made lookups
+		expected int
+	}{
+		{name: "full refresh", enriched: []domain.DependencyNode{express, qs}, made: lookups{projects: 2}, expected: 0},
+		{name: "everything reused", reused: []domain.DependencyNode{express, qs, ms}, expected: 6},
+		{name: "incremental", reused: []domain.DependencyNode{express, qs, ms}, enriched: []domain.DependencyNode{debug}, made: lookups{projects: 1}, expected: 4},
+		{name: "stored project of enriched node", reused: []domain.DependencyNode{express}, enriched: []domain.DependencyNode{debug}, made: lookups{projects: 0}, expected: 2},
+		{name: "version fallback", reused: []domain.DependencyNode{express, qs, ms}, enriched: []domain.DependencyNode{debug}, made: lookups{projects: 1, versionFallback: true}, expected: 7},
+		{name: "project fallback", reused: []domain.DependencyNode{express, qs, ms}, enriched: []domain.DependencyNode{debug}, made: lookups{projects: 1, projectFallback: true}, expected: 6},
+		{name: "duplicate versions", reused: []domain.DependencyNode{express, express}, enriched: []domain.DependencyNode{debug}, made: lookups{projects: 1}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if saved := savedCalls(tt.reused, tt.enriched, tt.made); saved != tt.expected {
+				t.Errorf("Got %d saved calls, expected %d", saved, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func summarize(pkg *domain.Package) domain.PackageSummary {
+	summary := domain.PackageSummary{
+		PackageRef: pkg.PackageRef,
+		LastUpdatedAt: pkg.LastUpdatedAt,
+		Health: pkg.Health,
+		Dependencies: len(pkg.Dependencies),
+	}
+	var total float64
+	for _, node := range pkg.Dependencies {
+		if node.Score == nil {
+			summary.Unscored = append(summary.Unscored, node)
+		} else {
+			summary.Scored++
+			total += *node.Score
+			if summary.MinScore == nil || *node.Score < *summary.MinScore {
+				score := *node.Score
+				summary.MinScore = &score
+			}
+		}
+		if node.Status == domain.EnrichmentFetchError {
+			summary.Failed = append(summary.Failed, node)
+		}
+		if node.Deprecated {
+			summary.Deprecated = append(summary.Deprecated, node)
+		}
+		if len(node.Advisories) > 0 {
+			summary.Vulnerable = append(summary.Vulnerable, node)
+			summary.Advisories += len(node.Advisories)
+		}
+	}
+	if summary.Scored > 0 {
+		average := total / float64(summary.Scored)
+		summary.AverageScore = &average
+	}
+	return summary
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+	"github.com/JCzapla/dep-dashboard/internal/port/outbound"
+)
+
+type packageLookup struct {
+	info domain.PackageInfo
+	err error
+}
+
+type packageCache struct {
+	client outbound.DepsDevClient
+	mu sync.Mutex
+	packages map[string]packageLookup
+}
+
+func newPackageCache(client outbound.DepsDevClient) *packageCache {
+	return &packageCache{client: client, packages: make(map[string]packageLookup)}
+}
+
+func (c *packageCache) get(ctx context.Context, name string) (domain.PackageInfo, error) {
+	c.mu.Lock()
+	lookup, ok := c.packages[name]
+	c.mu.Unlock()
+	if ok {
+		return lookup.info, lookup.err
+	}
+
+	lookup.info, lookup.err = c.client.FetchPackage(ctx, name)
+	if lookup.err != nil {
+		lookup.err = fmt.Errorf("Package %s lookup error: %w", name, lookup.err)
+	}
+	c.mu.Lock()
+	c.packages[name] = lookup
+	c.mu.Unlock()
+	return lookup.info, lookup.err
+}
+
+func versionLag(current, latest string) domain.VersionLag {
+	cur, ok := parseVersion(current)
+	if !ok {
+		return domain.VersionLag{}
+	}
+	last, ok := parseVersion(latest)
+	if !ok {
+		return domain.VersionLag{}
+	}
+
+	switch {
+	case last[0] != cur[0]:
+		return domain.VersionLag{Major: max(last[0]-cur[0], 0)}
+	case last[1] != cur[1]:
+		return domain.VersionLag{Minor: max(last[1]-cur[1], 0)}
+	default:
+		return domain.VersionLag{Patch: max(last[2]-cur[2], 0)}
+	}
+}
+
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
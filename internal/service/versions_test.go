@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JCzapla/dep-dashboard/internal/adapter/outbound/memory"
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func TestVersionLag(t *testing.T) {
+	tests := []struct {
+		name string
+		current string
+		latest string
+		expected domain.VersionLag
+	}{
+		{name: "up to date", current: "1.2.3", latest: "1.2.3", expected: domain.VersionLag{}},
+		{name: "major", current: "4.17.1", latest: "5.2.0", expected: domain.VersionLag{Major: 1}},
+		{name: "minor", current: "1.2.3", latest: "1.5.0", expected: domain.VersionLag{Minor: 3}},
+		{name: "patch", current: "1.2.3", latest: "1.2.9", expected: domain.VersionLag{Patch: 6}},
+		{name: "prerelease and build metadata", current: "v2.0.0-beta.1", latest: "2.1.0+build.5", expected: domain.VersionLag{Minor: 1}},
+		{name: "ahead of default version", current: "3.0.0", latest: "2.9.9", expected: domain.VersionLag{}},
+		{name: "unknown latest", current: "1.0.0", latest: "", expected: domain.VersionLag{}},
+		{name: "not semver", current: "latest", latest: "1.0.0", expected: domain.VersionLag{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := versionLag(tt.current, tt.latest); got != tt.expected {
+				t.Errorf("Got lag %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+type packageErrorClient struct {
+	*fakeClient
+	packageCalls int
+}
+
+func (c *packageErrorClient) FetchPackage(ctx context.Context, name string) (domain.PackageInfo, error) {
+	c.mu.Lock()
+	c.packageCalls++
+	c.mu.Unlock()
+	return domain.PackageInfo{}, errors.New("Error from deps.dev: 503")
+}
+
+func TestPackageCacheKeepsErrors(t *testing.T) {
+	client := &packageErrorClient{fakeClient: &fakeClient{}}
+	cache := newPackageCache(client)
+	for range 2 {
+		_, err := cache.get(context.Background(), "qs")
+		if err == nil || !strings.Contains(err.Error(), "Package qs lookup error") {
+			t.Errorf("Got error %v, expected wrapped lookup error", err)
+		}
+	}
+	if client.packageCalls != 1 {
+		t.Errorf("Got %d package calls, expected failed lookup to be cached", client.packageCalls)
+	}
+}
+
+func TestEnrichmentWithoutPackageInfo(t *testing.T) {
+	client := &packageErrorClient{fakeClient: &fakeClient{version: "1.0.0", scores: map[string]float64{"qs": 6}}}
+	service := NewDependencyService(memory.NewRepository(), nil, nil, client, &recordingPublisher{}, Config{})
+	nodes := []domain.DependencyNode{{Name: "qs", Version: "0.9.0", Relation: "DIRECT", Depth: 1}}
+	service.enrichWithScores(context.Background(), nodes)
+
+	if nodes[0].Status != domain.EnrichmentScored || nodes[0].LatestVersion != "" || nodes[0].Behind != (domain.VersionLag{}) {
+		t.Errorf("Got node %+v, expected scored node without outdated data", nodes[0])
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/JCzapla/dep-dashboard/internal/domain"
+)
+
+func (s *DependencyService) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", domain.ErrInvalidWebhook)
+	}
+	for _, event := range webhook.Events {
+		if !slices.Contains(domain.EventTypes, event) {
+			return fmt.Errorf("%w: unknown event %q", domain.ErrInvalidWebhook, event)
+		}
+	}
+	switch webhook.Format {
+	case "":
+		webhook.Format = domain.WebhookFormatJSON
+	case domain.WebhookFormatJSON, domain.WebhookFormatSlack:
+	default:
+		return fmt.Errorf("%w: unknown format %q", domain.ErrInvalidWebhook, webhook.Format)
+	}
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("Generating secret error: %w", err)
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	webhook.CreatedAt = time.Now().UTC()
+
+	return s.webhooks.CreateWebhook(ctx, webhook)
+}
+
+func (s *DependencyService) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	return s.webhooks.ListWebhooks(ctx)
+}
+
+func (s *DependencyService) DeleteWebhook(ctx context.Context, id int64) error {
+	return s.webhooks.DeleteWebhook(ctx, id)
+}
+
+func (s *DependencyService) ListWebhookDeliveries(ctx context.Context, id int64) ([]domain.WebhookDelivery, error) {
+	return s.webhooks.ListDeliveries(ctx, id)
+}